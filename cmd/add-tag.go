@@ -13,6 +13,8 @@ var (
 	promoteFrom string
 	hotFix      bool
 	service     string
+	tagRemote   string
+	noFetch     bool
 )
 
 // addTagCmd represents the add-tag command
@@ -37,6 +39,8 @@ func init() {
 	addTagCmd.Flags().StringVarP(&promoteFrom, "from", "f", "", "tag to promote from")
 	addTagCmd.Flags().BoolVar(&hotFix, "hot-fix", false, "tag hot fix")
 	addTagCmd.Flags().StringVarP(&service, "service", "s", "", "service name to tag")
+	addTagCmd.Flags().StringVar(&tagRemote, "remote", "", "remote to check and push to (default: remote setting or origin)")
+	addTagCmd.Flags().BoolVar(&noFetch, "no-fetch", false, "skip fetching remote branches and tags before tagging")
 }
 
 func runAddTag(cmd *cobra.Command, args []string) {
@@ -62,6 +66,19 @@ func runAddTag(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Resolve the service first, so the config of its repository applies to
+	// the branch rules and remotes below
+	scope, err := resolveServiceScope(service)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	projectPath := scope.Dir
+	if projectPath == "" {
+		// Use current working directory when no service specified
+		projectPath = "."
+	}
+
 	// Check current branch
 	branch, err := utils.Cmd("git rev-parse --abbrev-ref HEAD")
 	if err != nil {
@@ -69,66 +86,55 @@ func runAddTag(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	rules, err := loadBranchRules()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	branchRule := rules.Match(branch).Rule
+
 	// Validate branch and hot fix rules
-	if version != "last" && utils.IsReleaseBranch(branch) && !hotFix {
-		fmt.Fprintf(os.Stderr, "Error: you can tag only hot fix (use --hot-fix flag) from release branch\n")
+	if err := checkHotFixBranch(branchRule, version, hotFix); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Check the environments the branch may be tagged for
+	if version != "last" {
+		checkBranchEnvironment(branchRule, branch, environment)
+	}
+
 	// Check if not on master/main and not hot fix
-	if branch != "master" && branch != "main" && !hotFix {
+	if branchRule.Type != utils.BranchTypeMain && !hotFix {
 		if utils.Ask(fmt.Sprintf("Current branch is %s. Continue? (y/n)", branch)) != "y" {
 			os.Exit(0)
 		}
 	}
 
-	// Hot fix must be from release branch
-	if hotFix && !utils.IsReleaseBranch(branch) {
-		fmt.Fprintf(os.Stderr, "Error: hot fix must be tagged from release branch\n")
-		os.Exit(1)
+	// Refresh remote branches and tags so the sync check and the next tag are current
+	remotes := tagRemotes(service, tagRemote)
+	if !noFetch && fetchBeforeTagPolicy(service) {
+		if err := fetchRemotes(utils.Cmd, remotes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	// Check if local and remote are synced
-	sha, err := utils.Cmd("git rev-list HEAD | head -1")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting local SHA: %v\n", err)
+	// Check if local and every remote are synced
+	fmt.Printf("Checking %s is in sync with %v\n", branch, remotes)
+	if err := checkRemoteSync(utils.Cmd, branch, remotes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	shaRemote, err := utils.Cmd(fmt.Sprintf("git rev-list origin/%s | head -1", branch))
+	sha, err := utils.Cmd("git rev-parse HEAD")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting remote SHA: %v\n", err)
-		os.Exit(1)
-	}
-
-	if sha != shaRemote {
-		fmt.Fprintf(os.Stderr, "Error: remote is not synced\n")
+		fmt.Fprintf(os.Stderr, "Error getting local SHA: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Get last tag for version
-	var lastTag string
-	var projectPath string
-
-	if service == "" {
-		// Use current working directory when no service specified
-		projectPath = "."
-		lastTag, _, err = utils.FindLastTagAndCommentInDir(environment, version, "", projectPath)
-	} else {
-		// Make sure config is loaded when service is specified
-		initConfig()
-
-		// Find the project path for the specified service
-		projectPath = findProjectPath(service)
-		if projectPath == "" {
-			fmt.Fprintf(os.Stderr, "Error: service '%s' not found in configuration.\n", service)
-			os.Exit(1)
-		}
-
-		// Use the project directory when service is specified
-		lastTag, _, err = utils.FindLastTagAndCommentInDir(environment, version, "", projectPath)
-	}
-
+	// Get last tag for version from the service directory (or current directory)
+	lastTag, _, err := utils.FindLastTagAndCommentInDir(environment, version, "", projectPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error finding last tag in %s: %v\n", projectPath, err)
 		os.Exit(1)
@@ -157,6 +163,7 @@ func runAddTag(cmd *cobra.Command, args []string) {
 		}
 
 		newTag = strings.Replace(promoteFrom, promoteFromEnv, environment, 1)
+		warnDownstreamVersions(environment, newTag, projectPath)
 		if utils.Ask(fmt.Sprintf("promote %s to %s? (y/n)", promoteFrom, newTag)) != "y" {
 			os.Exit(0)
 		}
@@ -173,6 +180,7 @@ func runAddTag(cmd *cobra.Command, args []string) {
 			newTag = fmt.Sprintf("%s-0", utils.TagPrefix(environment, version, service))
 		}
 
+		warnDownstreamVersions(environment, newTag, projectPath)
 		if utils.Ask(fmt.Sprintf("add %s? (y/n)", newTag)) != "y" {
 			os.Exit(0)
 		}
@@ -184,18 +192,63 @@ func runAddTag(cmd *cobra.Command, args []string) {
 		newTagComment = newTag
 	}
 
-	// Tag and push
-	_, err = utils.Cmd(fmt.Sprintf("git tag -a %s -m \"%s\" %s", newTag, newTagComment, newTagCommit))
+	// Tag and push under the tag lock, moving to the next release number if the
+	// tag was taken meanwhile
+	newTag, err = lockAndPushTag(utils.Cmd, newTag, newTagComment, newTagCommit, remotes, nextReleaseNumber(utils.Cmd, hotFix))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating tag: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	_, err = utils.Cmd(fmt.Sprintf("git push origin %s", newTag))
+	fmt.Printf("Successfully created and pushed tag: %s\n", newTag)
+}
+
+// warnDownstreamVersions warns when a tag's version is lower than the latest
+// version of an environment further down the promotion order
+func warnDownstreamVersions(environment, newTag, projectPath string) {
+	newVersion, err := utils.GetVersionFromTag(newTag)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error pushing tag: %v\n", err)
-		os.Exit(1)
+		return
 	}
 
-	fmt.Printf("Successfully created and pushed tag: %s\n", newTag)
+	versions, err := utils.LatestEnvironmentVersions(service, projectPath)
+	if err != nil {
+		return
+	}
+
+	for _, warning := range downstreamVersionWarnings(environment, newVersion, versions) {
+		fmt.Printf("⚠️  Warning: %s\n", warning)
+	}
+}
+
+// downstreamVersionWarnings lists the downstream environments already ahead of version
+func downstreamVersionWarnings(environment, version string, versions []utils.EnvironmentVersion) []string {
+	downstream := utils.DownstreamEnvironments(environment)
+
+	var warnings []string
+	for _, deployed := range versions {
+		if !utils.ContainsString(downstream, deployed.Environment) {
+			continue
+		}
+		if cmp, err := utils.CompareSemanticVersions(version, deployed.Version); err == nil && cmp < 0 {
+			warnings = append(warnings, fmt.Sprintf("%s is lower than %s already in %s (%s)",
+				version, deployed.Version, deployed.Environment, deployed.Tag))
+		}
+	}
+	return warnings
+}
+
+// checkHotFixBranch enforces that release branches are tagged with hot fixes
+// only and that hot fixes are tagged from release branches. Release branches
+// are those of type release under the branch rules, which include release/*
+// and release_X.Y by default.
+func checkHotFixBranch(rule utils.BranchRule, version string, hotFix bool) error {
+	releaseBranch := rule.Type == utils.BranchTypeRelease
+	if version != "last" && releaseBranch && !hotFix {
+		return fmt.Errorf("you can tag only hot fix (use --hot-fix flag) from release branch")
+	}
+	if hotFix && !releaseBranch {
+		return fmt.Errorf("hot fix must be tagged from release branch")
+	}
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func TestAddTagCmdCreation(t *testing.T) {
@@ -450,3 +451,83 @@ func TestRunAddTagPromoteLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestDownstreamVersionWarnings(t *testing.T) {
+	versions := []utils.EnvironmentVersion{
+		{Environment: "dev", Tag: "dev_2.3.0-1", Version: "2.3.0"},
+		{Environment: "stg6", Tag: "stg6_2.1.0-2", Version: "2.1.0"},
+		{Environment: "production2", Tag: "production2_2.0.0-1", Version: "2.0.0"},
+	}
+
+	tests := []struct {
+		name        string
+		environment string
+		version     string
+		want        int
+	}{
+		{"ahead of all downstream", "mimic2", "2.2.0", 0},
+		{"behind staging and production", "mimic2", "1.9.0", 2},
+		{"behind staging only", "mimic2", "2.0.5", 1},
+		{"upstream versions ignored", "stg6", "2.0.5", 0},
+		{"last environment has no downstream", "production2", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := downstreamVersionWarnings(tt.environment, tt.version, versions)
+			if len(warnings) != tt.want {
+				t.Errorf("downstreamVersionWarnings(%s, %s) = %v, want %d warnings", tt.environment, tt.version, warnings, tt.want)
+			}
+		})
+	}
+
+	warnings := downstreamVersionWarnings("dev", "1.9.0", versions[2:])
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "production2_2.0.0-1") {
+		t.Errorf("warning should name the downstream tag, got %v", warnings)
+	}
+}
+
+func TestCheckHotFixBranch(t *testing.T) {
+	defer viper.Reset()
+
+	tests := []struct {
+		name    string
+		branch  string
+		version string
+		hotFix  bool
+		wantErr bool
+	}{
+		{"hot fix from release_X.Y", "release_1.2", "1.2.0", true, false},
+		{"hot fix from release/*", "release/1.2", "1.2.0", true, false},
+		{"release_X.Y requires --hot-fix", "release_1.2", "1.2.0", false, true},
+		{"release/* requires --hot-fix", "release/1.2", "1.2.0", false, true},
+		{"last allowed on release branch", "release/1.2", "last", false, false},
+		{"hot fix from main", "main", "1.2.0", true, true},
+		{"feature branch without --hot-fix", "feature/login", "1.2.0", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := loadBranchRules()
+			if err != nil {
+				t.Fatalf("loadBranchRules() unexpected error: %v", err)
+			}
+			err = checkHotFixBranch(rules.Match(tt.branch).Rule, tt.version, tt.hotFix)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkHotFixBranch(%s, %s, %t) error = %v, wantErr %t", tt.branch, tt.version, tt.hotFix, err, tt.wantErr)
+			}
+		})
+	}
+
+	// A configured rule can give release/* branches another type
+	viper.Set("branches", []interface{}{
+		map[string]interface{}{"pattern": "release/*", "type": "feature"},
+	})
+	rules, err := loadBranchRules()
+	if err != nil {
+		t.Fatalf("loadBranchRules() unexpected error: %v", err)
+	}
+	if err := checkHotFixBranch(rules.Match("release/1.2").Rule, "1.2.0", false); err != nil {
+		t.Errorf("release/* configured as feature should not require --hot-fix, got %v", err)
+	}
+}
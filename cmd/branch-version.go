@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -15,6 +16,8 @@ var (
 	branchReleasePrep bool
 	branchEnvironment string
 	branchService     string
+	branchRemote      string
+	branchNoFetch     bool
 )
 
 // branchVersionCmd represents the branch-version command
@@ -29,11 +32,15 @@ This command provides intelligent version management based on the current git br
 - Can automatically create tags for release preparation
 - Integrates with conventional commit analysis
 
-Branch naming conventions supported:
-- feature/* → minor version bump
-- hotfix/* → patch version bump  
-- release/* → prepare for release tagging
-- develop/main → analyze commits for bump type`,
+Default branch naming conventions:
+- feature/*, feat/* → minor version bump
+- hotfix/*, fix/*, bugfix/*, chore/* → patch version bump
+- release/*, release_X.Y → prepare for release tagging
+- develop/main → analyze commits for bump type
+
+Rules in the branches configuration section take precedence over the defaults.
+Each maps a glob pattern or regex to a branch type, a bump type (major, minor,
+patch or auto) and optionally the environments the branch may be tagged for.`,
 	Example: `  esh-cli branch-version --suggest                # Suggest version bump for current branch
   esh-cli branch-version --auto-tag stg6          # Auto-create tag based on branch
   esh-cli branch-version --release-prep           # Prepare for release workflow
@@ -46,6 +53,7 @@ type BranchInfo struct {
 	Type     string
 	Feature  string
 	Strategy string
+	Rule     utils.BranchRule
 }
 
 func init() {
@@ -56,6 +64,8 @@ func init() {
 	branchVersionCmd.Flags().BoolVar(&branchReleasePrep, "release-prep", false, "Prepare release branch workflow")
 	branchVersionCmd.Flags().StringVarP(&branchEnvironment, "env", "e", "", "Target environment for tagging")
 	branchVersionCmd.Flags().StringVarP(&branchService, "service", "s", "", "Service name for tagging")
+	branchVersionCmd.Flags().StringVar(&branchRemote, "remote", "", "Remote to push auto-created tags to (default: remote setting or origin)")
+	branchVersionCmd.Flags().BoolVar(&branchNoFetch, "no-fetch", false, "Skip fetching remote tags before auto-tagging")
 }
 
 func runBranchVersion(cmd *cobra.Command, args []string) {
@@ -96,54 +106,39 @@ func runBranchVersion(cmd *cobra.Command, args []string) {
 }
 
 func analyzeBranch(branchName string) BranchInfo {
-	info := BranchInfo{
-		Name: branchName,
-		Type: "unknown",
+	rules, err := loadBranchRules()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using default branch rules\n", err)
+		rules = utils.DefaultBranchRuleSet()
 	}
 
-	nameLower := strings.ToLower(branchName)
-
-	// Analyze branch type and extract feature name
-	if strings.HasPrefix(nameLower, "feature/") {
-		info.Type = "feature"
-		info.Feature = strings.TrimPrefix(branchName, "feature/")
-		info.Strategy = "minor version bump (new features)"
-	} else if strings.HasPrefix(nameLower, "feat/") {
-		info.Type = "feature"
-		info.Feature = strings.TrimPrefix(branchName, "feat/")
-		info.Strategy = "minor version bump (new features)"
-	} else if strings.HasPrefix(nameLower, "hotfix/") {
-		info.Type = "hotfix"
-		info.Feature = strings.TrimPrefix(branchName, "hotfix/")
-		info.Strategy = "patch version bump (bug fixes)"
-	} else if strings.HasPrefix(nameLower, "fix/") {
-		info.Type = "hotfix"
-		info.Feature = strings.TrimPrefix(branchName, "fix/")
-		info.Strategy = "patch version bump (bug fixes)"
-	} else if strings.HasPrefix(nameLower, "release/") {
-		info.Type = "release"
-		info.Feature = strings.TrimPrefix(branchName, "release/")
-		info.Strategy = "prepare for release tagging"
-	} else if nameLower == "develop" || nameLower == "development" {
-		info.Type = "develop"
-		info.Strategy = "analyze commits for bump type"
-	} else if nameLower == "main" || nameLower == "master" {
-		info.Type = "main"
-		info.Strategy = "analyze commits for bump type"
-	} else if strings.HasPrefix(nameLower, "bugfix/") {
-		info.Type = "bugfix"
-		info.Feature = strings.TrimPrefix(branchName, "bugfix/")
-		info.Strategy = "patch version bump (bug fixes)"
-	} else if strings.HasPrefix(nameLower, "chore/") {
-		info.Type = "chore"
-		info.Feature = strings.TrimPrefix(branchName, "chore/")
-		info.Strategy = "patch version bump (maintenance)"
-	} else {
-		info.Type = "custom"
-		info.Strategy = "analyze commits or manual specification"
+	match := rules.Match(branchName)
+	return BranchInfo{
+		Name:     branchName,
+		Type:     match.Rule.Type,
+		Feature:  match.Name,
+		Strategy: match.Rule.Strategy,
+		Rule:     match.Rule,
+	}
+}
+
+// loadBranchRules reads the branches section of the configuration, whose rules
+// take precedence over the built-in git flow rules
+func loadBranchRules() (utils.BranchRules, error) {
+	var configured []utils.BranchRule
+	if err := viper.UnmarshalKey("branches", &configured); err != nil {
+		return nil, fmt.Errorf("invalid branches configuration: %v", err)
 	}
+	return utils.NewBranchRules(configured)
+}
 
-	return info
+// checkBranchEnvironment exits when the branch rule does not allow tagging the environment
+func checkBranchEnvironment(rule utils.BranchRule, branch, environment string) {
+	if !rule.AllowsEnvironment(environment) {
+		fmt.Fprintf(os.Stderr, "Error: %s branches (%s) can only be tagged for: %s\n",
+			rule.Type, branch, strings.Join(rule.Environments, ", "))
+		os.Exit(1)
+	}
 }
 
 func suggestVersionBump(branchInfo BranchInfo) {
@@ -151,33 +146,34 @@ func suggestVersionBump(branchInfo BranchInfo) {
 
 	var bumpType utils.BumpType
 
-	switch branchInfo.Type {
-	case "feature":
-		bumpType = utils.BumpMinor
-		fmt.Printf("Recommended: MINOR bump (new features)\n")
-		fmt.Printf("Reason: Feature branch detected\n")
-	case "hotfix", "bugfix":
-		bumpType = utils.BumpPatch
-		fmt.Printf("Recommended: PATCH bump (bug fixes)\n")
-		fmt.Printf("Reason: Fix branch detected\n")
-	case "release":
+	switch {
+	case branchInfo.Type == utils.BranchTypeRelease:
 		fmt.Printf("Recommended: Review and prepare for release\n")
 		fmt.Printf("Reason: Release branch - version should be finalized\n")
 		showReleasePreparation(branchInfo)
 		return
-	case "develop", "main":
-		// Analyze commits to suggest bump type
+	case branchInfo.Rule.Bump == "" || branchInfo.Rule.Bump == utils.BumpAuto:
 		bumpType = analyzeCommitsForBump()
 		fmt.Printf("Recommended: %s bump (based on commit analysis)\n", bumpType)
-		fmt.Printf("Reason: Main/develop branch - analyzing recent commits\n")
-	case "chore":
-		bumpType = utils.BumpPatch
-		fmt.Printf("Recommended: PATCH bump (maintenance)\n")
-		fmt.Printf("Reason: Chore/maintenance branch\n")
+		fmt.Printf("Reason: %s branch - analyzing recent commits\n", branchInfo.Type)
 	default:
-		bumpType = analyzeCommitsForBump()
-		fmt.Printf("Recommended: %s bump (based on commit analysis)\n", bumpType)
-		fmt.Printf("Reason: Custom branch - analyzing commits\n")
+		bumpType = branchInfo.Rule.Bump
+		fmt.Printf("Recommended: %s bump (%s)\n", strings.ToUpper(string(bumpType)), branchInfo.Strategy)
+		fmt.Printf("Reason: %s branch detected\n", branchInfo.Type)
+	}
+
+	if len(branchInfo.Rule.Environments) > 0 {
+		fmt.Printf("Allowed environments: %s\n", strings.Join(branchInfo.Rule.Environments, ", "))
+	}
+
+	// Breaking changes in 0.x bump minor under the initial development policy
+	if bumpType == utils.BumpMajor && branchEnvironment != "" && initialDevelopmentPolicy(branchService) {
+		if _, latestVersion, err := utils.GetLatestSemanticVersion(branchEnvironment, branchService); err == nil {
+			if lowered, ok := utils.ApplyInitialDevelopment(latestVersion, bumpType); ok {
+				bumpType = lowered
+				fmt.Printf("Initial development: %s is below 1.0.0, breaking changes bump minor\n", latestVersion)
+			}
+		}
 	}
 
 	// Show example commands
@@ -206,24 +202,31 @@ func autoCreateTag(branchInfo BranchInfo, environment, service string) {
 		os.Exit(1)
 	}
 
+	checkBranchEnvironment(branchInfo.Rule, branchInfo.Name, environment)
+
 	var bumpType utils.BumpType
 
-	switch branchInfo.Type {
-	case "feature":
-		bumpType = utils.BumpMinor
-	case "hotfix", "bugfix":
-		bumpType = utils.BumpPatch
-	case "chore":
-		bumpType = utils.BumpPatch
-	case "release":
+	switch {
+	case branchInfo.Type == utils.BranchTypeRelease:
 		fmt.Printf("Release branch detected. Use 'esh-cli branch-version --release-prep' instead.\n")
 		return
-	default:
+	case branchInfo.Rule.Bump == "" || branchInfo.Rule.Bump == utils.BumpAuto:
 		bumpType = analyzeCommitsForBump()
+	default:
+		bumpType = branchInfo.Rule.Bump
 	}
 
 	fmt.Printf("Creating %s tag for %s environment...\n", bumpType, environment)
 
+	// Fetch tags created by teammates before computing the new tag
+	remotes := tagRemotes(service, branchRemote)
+	if !branchNoFetch && fetchBeforeTagPolicy(service) {
+		if err := fetchRemotes(utils.Cmd, remotes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Find latest tag
 	latestTag, latestVersion, err := utils.GetLatestSemanticVersion(environment, service)
 	if err != nil {
@@ -233,6 +236,14 @@ func autoCreateTag(branchInfo BranchInfo, environment, service string) {
 
 	fmt.Printf("Current latest: %s (%s)\n", latestTag, latestVersion)
 
+	// Breaking changes in 0.x bump minor under the initial development policy
+	if initialDevelopmentPolicy(service) {
+		if lowered, ok := utils.ApplyInitialDevelopment(latestVersion, bumpType); ok {
+			bumpType = lowered
+			fmt.Printf("Initial development: %s is below 1.0.0, bumping minor instead\n", latestVersion)
+		}
+	}
+
 	// Create new tag
 	newTag, err := utils.BumpTagVersion(latestTag, bumpType, environment, service)
 	if err != nil {
@@ -260,16 +271,10 @@ func autoCreateTag(branchInfo BranchInfo, environment, service string) {
 	}
 	commit = strings.TrimSpace(commit)
 
-	// Create and push tag
-	_, err = utils.Cmd(fmt.Sprintf("git tag -a %s -m \"%s\" %s", newTag, comment, commit))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating tag: %v\n", err)
-		os.Exit(1)
-	}
-
-	_, err = utils.Cmd(fmt.Sprintf("git push origin %s", newTag))
+	// Create and push tag under the tag lock
+	newTag, err = lockAndPushTag(utils.Cmd, newTag, comment, commit, remotes, bumpLatestTag(utils.Cmd, environment, service, "", bumpType))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error pushing tag: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -279,7 +284,7 @@ func autoCreateTag(branchInfo BranchInfo, environment, service string) {
 func prepareRelease(branchInfo BranchInfo) {
 	fmt.Printf("🚀 Release Preparation\n")
 
-	if branchInfo.Type == "release" {
+	if branchInfo.Type == utils.BranchTypeRelease {
 		fmt.Printf("Release branch detected: %s\n", branchInfo.Name)
 
 		// Extract version from release branch if possible
@@ -291,8 +296,8 @@ func prepareRelease(branchInfo BranchInfo) {
 	} else {
 		fmt.Printf("Current branch (%s) is not a release branch.\n", branchInfo.Name)
 		fmt.Printf("Consider creating a release branch first:\n")
-		fmt.Printf("  git checkout -b release/1.2.0\n")
-		fmt.Printf("  git push -u origin release/1.2.0\n")
+		fmt.Printf("  esh-cli release-branch create --preview\n")
+		fmt.Printf("  esh-cli release-branch create\n")
 	}
 }
 
@@ -324,16 +329,17 @@ func showReleasePreparation(branchInfo BranchInfo) {
 }
 
 func analyzeCommitsForBump() utils.BumpType {
-	// Get recent commits
-	output, err := utils.Cmd("git log --oneline -10 --pretty=format:\"%s\"")
-	if err != nil {
+	// Get recent commits, including bodies for BREAKING CHANGE footers
+	commits, err := utils.GetCommitMessagesInDir("-10 HEAD", "", nil)
+	if err != nil || len(commits) == 0 {
 		return utils.BumpPatch // Default fallback
 	}
 
-	if output == "" {
-		return utils.BumpPatch
+	taxonomy, err := loadCommitTaxonomy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using default commit types\n", err)
+		taxonomy = utils.DefaultCommitTaxonomy()
 	}
 
-	commits := strings.Split(output, "\n")
-	return utils.DetectBumpType(commits)
+	return taxonomy.DecideBump(commits).Bump
 }
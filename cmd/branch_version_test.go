@@ -5,6 +5,8 @@ import (
 	"esh-cli/pkg/utils"
 	"strings"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestBranchVersionCmdCreation(t *testing.T) {
@@ -23,7 +25,7 @@ func TestBranchVersionCmdCreation(t *testing.T) {
 
 func TestBranchVersionFlags(t *testing.T) {
 	// Test that flags exist
-	flags := []string{"suggest", "auto-tag", "release-prep", "env", "service"}
+	flags := []string{"suggest", "auto-tag", "release-prep", "env", "service", "remote", "no-fetch"}
 
 	for _, flagName := range flags {
 		flag := branchVersionCmd.Flags().Lookup(flagName)
@@ -560,3 +562,42 @@ func TestRunBranchVersionWorkflow(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeBranchConfiguredRules(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("branches", []interface{}{
+		map[string]interface{}{
+			"pattern":      "story/*",
+			"type":         "feature",
+			"bump":         "minor",
+			"environments": []interface{}{"dev", "mimic2"},
+		},
+		map[string]interface{}{
+			"regex": `^v(\d+\.\d+)-maintenance$`,
+			"type":  "release",
+		},
+	})
+
+	story := analyzeBranch("story/checkout")
+	if story.Type != "feature" || story.Feature != "checkout" || story.Rule.Bump != utils.BumpMinor {
+		t.Errorf("story branch analyzed as %+v", story)
+	}
+	if story.Rule.AllowsEnvironment("stg6") {
+		t.Error("story branches should not be allowed to tag stg6")
+	}
+
+	maintenance := analyzeBranch("v1.4-maintenance")
+	if maintenance.Type != utils.BranchTypeRelease || maintenance.Feature != "1.4" {
+		t.Errorf("maintenance branch analyzed as %+v", maintenance)
+	}
+
+	if hotfix := analyzeBranch("hotfix/urgent"); hotfix.Type != "hotfix" {
+		t.Errorf("default rules should still apply, got %s", hotfix.Type)
+	}
+
+	viper.Set("branches", []interface{}{map[string]interface{}{"type": "feature"}})
+	if _, err := loadBranchRules(); err == nil {
+		t.Error("Expected error for a branch rule without pattern or regex")
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"esh-cli/pkg/utils"
 	"fmt"
 	"os"
@@ -10,13 +11,20 @@ import (
 )
 
 var (
-	bumpMajor   bool
-	bumpMinor   bool
-	bumpPatch   bool
-	bumpAuto    bool
-	bumpPreview bool
-	bumpService string
-	fromCommit  string
+	bumpMajor    bool
+	bumpMinor    bool
+	bumpPatch    bool
+	bumpAuto     bool
+	bumpPreview  bool
+	bumpExplain  bool
+	bumpGrad     bool
+	bumpInitial  string
+	bumpSeedFrom string
+	bumpTrain    bool
+	bumpService  string
+	bumpRemote   string
+	bumpNoFetch  bool
+	fromCommit   string
 )
 
 // bumpVersionCmd represents the bump-version command
@@ -30,12 +38,28 @@ This command creates a new tag with an incremented semantic version based on the
 - --minor: Increment minor version (new features)  
 - --patch: Increment patch version (bug fixes)
 - --auto: Auto-detect bump type from commit messages (conventional commits)
+- --graduate: Release 1.0.0 from a 0.x version
 
-The new tag will have the format: env_major.minor.patch-1`,
+With initial_development enabled (top-level or per project), breaking changes
+of 0.x versions bump minor instead of releasing 1.0.0; use --graduate for that.
+
+The new tag will have the format: env_major.minor.patch-1
+
+When the environment has no tags yet, the first tag is created from --initial,
+from the latest version of the --seed-from environment, or from the
+initial_version setting (per project or top-level).
+
+With --train, all environments of a service share one version line: the next
+version is computed from the highest version tagged in any environment.`,
 	Example: `  esh-cli bump-version stg6 --major     # 1.2.3 → 2.0.0-1
   esh-cli bump-version stg6 --minor     # 1.2.3 → 1.3.0-1
   esh-cli bump-version stg6 --patch     # 1.2.3 → 1.2.4-1
   esh-cli bump-version stg6 --auto      # Auto-detect from commits
+  esh-cli bump-version stg6 --auto --explain --preview  # Show why a bump was chosen
+  esh-cli bump-version stg6 --graduate  # 0.9.2 → 1.0.0-1
+  esh-cli bump-version dev --initial 0.1.0      # First tag: dev_0.1.0-1
+  esh-cli bump-version stg6 --seed-from dev     # First tag at dev's latest version
+  esh-cli bump-version stg6 --minor --train     # dev at 2.3.0, stg6 at 1.9.0 → stg6_2.4.0-1
   esh-cli bump-version stg6 --major --preview  # Show what would be created
   esh-cli bump-version stg6 --patch --service myservice  # Service-specific tag`,
 	Args: cobra.ExactArgs(1),
@@ -49,12 +73,23 @@ func init() {
 	bumpVersionCmd.Flags().BoolVar(&bumpMinor, "minor", false, "bump minor version (new features)")
 	bumpVersionCmd.Flags().BoolVar(&bumpPatch, "patch", false, "bump patch version (bug fixes)")
 	bumpVersionCmd.Flags().BoolVar(&bumpAuto, "auto", false, "auto-detect bump type from commit messages")
+	bumpVersionCmd.Flags().BoolVar(&bumpGrad, "graduate", false, "release the first stable version 1.0.0 from 0.x")
 	bumpVersionCmd.Flags().BoolVar(&bumpPreview, "preview", false, "preview the change without creating tag")
+	bumpVersionCmd.Flags().BoolVar(&bumpExplain, "explain", false, "explain which commits and rules decided the auto-detected bump")
 	bumpVersionCmd.Flags().StringVarP(&bumpService, "service", "s", "", "service name to tag")
 	bumpVersionCmd.Flags().StringVar(&fromCommit, "from-commit", "HEAD", "commit to tag (default: HEAD)")
+	bumpVersionCmd.Flags().StringVar(&bumpRemote, "remote", "", "remote to push to (default: remote setting or origin)")
+	bumpVersionCmd.Flags().BoolVar(&bumpNoFetch, "no-fetch", false, "skip fetching remote tags before computing the new tag")
+
+	bumpVersionCmd.Flags().StringVar(&bumpInitial, "initial", "", "version of the first tag when the environment has no tags (e.g. 0.1.0)")
+	bumpVersionCmd.Flags().StringVar(&bumpSeedFrom, "seed-from", "", "start an environment without tags at another environment's latest version")
+
+	bumpVersionCmd.Flags().BoolVar(&bumpTrain, "train", false, "bump from the highest version across all environments")
 
 	// Mark flags as mutually exclusive
-	bumpVersionCmd.MarkFlagsMutuallyExclusive("major", "minor", "patch", "auto")
+	bumpVersionCmd.MarkFlagsMutuallyExclusive("major", "minor", "patch", "auto", "graduate", "initial", "seed-from")
+	bumpVersionCmd.MarkFlagsMutuallyExclusive("train", "initial")
+	bumpVersionCmd.MarkFlagsMutuallyExclusive("train", "seed-from")
 }
 
 func runBumpVersion(cmd *cobra.Command, args []string) {
@@ -81,70 +116,66 @@ func runBumpVersion(cmd *cobra.Command, args []string) {
 	if bumpAuto {
 		bumpCount++
 	}
+	if bumpGrad {
+		bumpCount++
+	}
 
-	if bumpCount == 0 {
-		fmt.Fprintf(os.Stderr, "Error: must specify one of --major, --minor, --patch, or --auto\n")
+	if bumpCount == 0 && bumpInitial == "" && bumpSeedFrom == "" {
+		fmt.Fprintf(os.Stderr, "Error: must specify one of --major, --minor, --patch, --auto, --graduate, --initial or --seed-from\n")
 		os.Exit(1)
 	}
 
-	// Get current working directory for tag operations
-	var projectPath string
-	if bumpService == "" {
-		projectPath = "."
-	} else {
-		initConfig()
-		projectPath = findProjectPath(bumpService)
-		if projectPath == "" {
-			fmt.Fprintf(os.Stderr, "Error: service '%s' not found in configuration.\n", bumpService)
-			os.Exit(1)
-		}
+	if bumpExplain && !bumpAuto {
+		fmt.Fprintf(os.Stderr, "Error: --explain requires --auto\n")
+		os.Exit(1)
 	}
 
-	// Find the latest tag for the environment
-	latestTag, latestVersion, err := utils.GetLatestSemanticVersion(environment, bumpService)
+	// Resolve the service directory and monorepo paths for tag operations
+	scope, err := resolveServiceScope(bumpService)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error finding latest version: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Current latest tag: %s (version: %s)\n", latestTag, latestVersion)
-
-	// Determine bump type
-	var bumpType utils.BumpType
-	if bumpMajor {
-		bumpType = utils.BumpMajor
-	} else if bumpMinor {
-		bumpType = utils.BumpMinor
-	} else if bumpPatch {
-		bumpType = utils.BumpPatch
-	} else if bumpAuto {
-		// Auto-detect from commits since last tag
-		commits, err := utils.GetCommitsBetweenTags(latestTag, fromCommit)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error getting commits since last tag: %v\n", err)
-			os.Exit(1)
-		}
-
-		if len(commits) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: no commits found since last tag %s\n", latestTag)
+	// Fetch tags created by teammates before computing the new tag
+	remotes := tagRemotes(bumpService, bumpRemote)
+	if !bumpNoFetch && fetchBeforeTagPolicy(bumpService) {
+		if err := fetchRemotes(scope.git, remotes); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-
-		bumpType = utils.DetectBumpType(commits)
-		fmt.Printf("Auto-detected bump type: %s (analyzed %d commits)\n", bumpType, len(commits))
 	}
 
-	// Create new tag with bumped version
-	newTag, err := utils.BumpTagVersion(latestTag, bumpType, environment, bumpService)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating new tag: %v\n", err)
+	// Find the latest tag for the environment, or bootstrap the first one
+	var bumpType utils.BumpType
+	var newTag string
+
+	latestTag, latestVersion, err := utils.GetLatestSemanticVersionInDir(environment, bumpService, scope.Dir)
+	noTags := errors.Is(err, utils.ErrNoTags)
+	switch {
+	case err != nil && !noTags:
+		fmt.Fprintf(os.Stderr, "Error finding latest version: %v\n", err)
+		os.Exit(1)
+	case noTags && !bumpTrain:
+		bumpType, newTag = bootstrapFirstTag(environment, scope, err)
+	case !noTags && (bumpInitial != "" || bumpSeedFrom != ""):
+		fmt.Fprintf(os.Stderr, "Error: %s already has tags (latest: %s), --initial and --seed-from only create the first tag\n",
+			environment, latestTag)
 		os.Exit(1)
+	default:
+		if latestTag != "" {
+			fmt.Printf("Current latest tag: %s (version: %s)\n", latestTag, latestVersion)
+		}
+		if bumpTrain {
+			latestTag, latestVersion = trainBaseTag(environment, scope, latestTag)
+		}
+		bumpType, newTag = bumpFromLatest(environment, scope, latestTag, latestVersion)
 	}
 
 	// Preview mode - show what would be created
 	if bumpPreview {
 		fmt.Printf("\n🔍 Preview Mode:\n")
-		fmt.Printf("Current tag: %s\n", latestTag)
+		fmt.Printf("Current tag: %s\n", orNone(latestTag))
 		fmt.Printf("Bump type:   %s\n", bumpType)
 		fmt.Printf("New tag:     %s\n", newTag)
 		fmt.Printf("Target commit: %s\n", fromCommit)
@@ -159,7 +190,7 @@ func runBumpVersion(cmd *cobra.Command, args []string) {
 	}
 
 	// Resolve target commit
-	targetCommit, err := utils.Cmd(fmt.Sprintf("git rev-parse %s", fromCommit))
+	targetCommit, err := scope.git(fmt.Sprintf("git rev-parse %s", fromCommit))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error resolving commit %s: %v\n", fromCommit, err)
 		os.Exit(1)
@@ -167,6 +198,9 @@ func runBumpVersion(cmd *cobra.Command, args []string) {
 
 	// Get comment for the tag
 	defaultComment := fmt.Sprintf("Bump %s version: %s", bumpType, newTag)
+	if bumpType == utils.BumpInitial {
+		defaultComment = fmt.Sprintf("Initial version: %s", newTag)
+	}
 	comment := utils.Ask(fmt.Sprintf("Tag comment (default: %s)", defaultComment))
 	if strings.TrimSpace(comment) == "" {
 		comment = defaultComment
@@ -175,15 +209,15 @@ func runBumpVersion(cmd *cobra.Command, args []string) {
 	// Create and push the tag
 	fmt.Printf("Creating tag %s on commit %s...\n", newTag, targetCommit[:8])
 
-	_, err = utils.Cmd(fmt.Sprintf("git tag -a %s -m \"%s\" %s", newTag, comment, targetCommit))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating tag: %v\n", err)
-		os.Exit(1)
+	// A tag taken meanwhile is bumped again from the new latest tag; initial
+	// and train tags keep their version and move to the next release number
+	recompute := bumpLatestTag(scope.git, environment, bumpService, scope.Dir, bumpType)
+	if bumpType == utils.BumpInitial || bumpTrain {
+		recompute = nextReleaseNumber(scope.git, false)
 	}
-
-	_, err = utils.Cmd(fmt.Sprintf("git push origin %s", newTag))
+	newTag, err = lockAndPushTag(scope.git, newTag, comment, targetCommit, remotes, recompute)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error pushing tag: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -191,9 +225,204 @@ func runBumpVersion(cmd *cobra.Command, args []string) {
 
 	// Show summary
 	fmt.Printf("\n📋 Summary:\n")
-	fmt.Printf("Previous: %s (%s)\n", latestTag, latestVersion)
+	if latestTag != "" {
+		fmt.Printf("Previous: %s (%s)\n", latestTag, latestVersion)
+	} else {
+		fmt.Printf("Previous: (none)\n")
+	}
 	newVersion, _ := utils.GetVersionFromTag(newTag)
 	fmt.Printf("New:      %s (%s)\n", newTag, newVersion)
 	fmt.Printf("Bump:     %s\n", bumpType)
 	fmt.Printf("Commit:   %s\n", targetCommit[:8])
 }
+
+// bootstrapFirstTag returns the first tag of an environment without tags. The
+// version comes from --initial, the --seed-from environment or the configured
+// initial_version, in that order.
+func bootstrapFirstTag(environment string, scope serviceScope, noTagsErr error) (utils.BumpType, string) {
+	version, source, err := resolveInitialVersion(environment, scope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if version == "" {
+		fmt.Fprintf(os.Stderr, "Error finding latest version: %v\n", noTagsErr)
+		fmt.Fprintf(os.Stderr, "Create the first tag with --initial X.Y.Z, --seed-from <environment>, or set initial_version in the configuration\n")
+		os.Exit(1)
+	}
+
+	newTag := fmt.Sprintf("%s-1", utils.TagPrefix(environment, version, bumpService))
+	if !utils.IsTagValid(newTag) {
+		fmt.Fprintf(os.Stderr, "Error: invalid tag: %s\n", newTag)
+		os.Exit(1)
+	}
+
+	fmt.Printf("No tags found for %s, creating the first tag from %s (version: %s)\n", environment, source, version)
+	return utils.BumpInitial, newTag
+}
+
+// resolveInitialVersion returns the version of an environment's first tag and
+// where it came from, or an empty version when none is configured
+func resolveInitialVersion(environment string, scope serviceScope) (string, string, error) {
+	if bumpInitial != "" {
+		if err := utils.ValidateInitialVersion(bumpInitial); err != nil {
+			return "", "", err
+		}
+		return bumpInitial, "--initial", nil
+	}
+
+	if bumpSeedFrom != "" {
+		if !utils.ContainsString(utils.ENVS, bumpSeedFrom) || bumpSeedFrom == environment {
+			return "", "", fmt.Errorf("invalid --seed-from environment '%s'. Valid environments: %v", bumpSeedFrom, utils.ENVS)
+		}
+		seedTag, seedVersion, err := utils.GetLatestSemanticVersionInDir(bumpSeedFrom, bumpService, scope.Dir)
+		if err != nil {
+			return "", "", fmt.Errorf("cannot seed from %s: %v", bumpSeedFrom, err)
+		}
+		return seedVersion, seedTag, nil
+	}
+
+	if version := initialVersionSetting(bumpService); version != "" {
+		if err := utils.ValidateInitialVersion(version); err != nil {
+			return "", "", fmt.Errorf("invalid initial_version: %v", err)
+		}
+		return version, "initial_version setting", nil
+	}
+
+	return "", "", nil
+}
+
+// orNone returns value, or "(none)" when it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// trainBaseTag returns the tag with the highest version across all
+// environments, which --train bumps from instead of the environment's own tag
+func trainBaseTag(environment string, scope serviceScope, latestTag string) (string, string) {
+	versions, err := utils.LatestEnvironmentVersions(bumpService, scope.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding environment versions: %v\n", err)
+		os.Exit(1)
+	}
+
+	highest, found := utils.HighestEnvironmentVersion(versions)
+	if !found {
+		fmt.Fprintf(os.Stderr, "Error: no tags found in any environment, create the first tag with --initial\n")
+		os.Exit(1)
+	}
+
+	fmt.Printf("Release train:\n")
+	for _, version := range versions {
+		marker := " "
+		if version.Tag == highest.Tag {
+			marker = "▶"
+		}
+		fmt.Printf("  %s %-12s %s\n", marker, version.Environment, version.Tag)
+	}
+
+	if highest.Tag != latestTag {
+		fmt.Printf("Bumping %s from %s (%s), the highest version across environments\n",
+			environment, highest.Version, highest.Environment)
+	}
+
+	return highest.Tag, highest.Version
+}
+
+// bumpFromLatest determines the bump type from the flags and commits since the
+// latest tag and returns it with the new tag
+func bumpFromLatest(environment string, scope serviceScope, latestTag, latestVersion string) (utils.BumpType, string) {
+	var bumpType utils.BumpType
+	if bumpMajor {
+		bumpType = utils.BumpMajor
+	} else if bumpMinor {
+		bumpType = utils.BumpMinor
+	} else if bumpPatch {
+		bumpType = utils.BumpPatch
+	} else if bumpGrad {
+		if !utils.IsInitialDevelopment(latestVersion) {
+			fmt.Fprintf(os.Stderr, "Error: %s is already at or above 1.0.0, nothing to graduate\n", latestTag)
+			os.Exit(1)
+		}
+		bumpType = utils.BumpGraduate
+	} else if bumpAuto {
+		// Auto-detect from commits since last tag, limited to the service paths
+		commits, err := utils.GetCommitMessagesInDir(fmt.Sprintf("%s..%s", latestTag, fromCommit), scope.Dir, scope.Paths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting commits since last tag: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(commits) == 0 {
+			if len(scope.Paths) > 0 {
+				fmt.Fprintf(os.Stderr, "Error: no commits touching %v found since last tag %s\n", scope.Paths, latestTag)
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: no commits found since last tag %s\n", latestTag)
+			}
+			os.Exit(1)
+		}
+
+		taxonomy, err := loadCommitTaxonomy()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading changelog configuration: %v\n", err)
+			os.Exit(1)
+		}
+
+		decision := taxonomy.DecideBump(commits)
+		if initialDevelopmentPolicy(bumpService) {
+			decision.ApplyInitialDevelopment(latestVersion)
+		}
+		bumpType = decision.Bump
+		fmt.Printf("Auto-detected bump type: %s (analyzed %d commits)\n", bumpType, len(commits))
+
+		if bumpExplain {
+			printBumpDecision(decision)
+		}
+	}
+
+	// Breaking changes in 0.x bump minor under the initial development policy
+	if bumpMajor && initialDevelopmentPolicy(bumpService) {
+		if lowered, ok := utils.ApplyInitialDevelopment(latestVersion, bumpType); ok {
+			fmt.Printf("Initial development: %s is below 1.0.0, bumping minor instead (use --graduate for 1.0.0)\n", latestVersion)
+			bumpType = lowered
+		}
+	}
+
+	// Create new tag with bumped version
+	newTag, err := utils.BumpTagVersion(latestTag, bumpType, environment, bumpService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating new tag: %v\n", err)
+		os.Exit(1)
+	}
+
+	return bumpType, newTag
+}
+
+// printBumpDecision explains which commits and rules led to an auto-detected bump
+func printBumpDecision(decision utils.BumpDecision) {
+	fmt.Printf("\n🔎 Bump Decision: %s\n", decision.Bump)
+	fmt.Printf("Analyzed: %s", pluralize(decision.Analyzed, "commit"))
+	if decision.Excluded > 0 {
+		fmt.Printf(" (%d excluded by changelog.exclude)", decision.Excluded)
+	}
+	fmt.Println()
+
+	if decision.Adjustment != "" {
+		fmt.Printf("Adjusted: %s\n", decision.Adjustment)
+	}
+
+	if len(decision.Triggers) == 0 {
+		fmt.Printf("No commits left to analyze, defaulting to %s\n\n", decision.Bump)
+		return
+	}
+
+	fmt.Printf("Triggered by:\n")
+	for _, trigger := range decision.Triggers {
+		fmt.Printf("  %s %s\n", shortHash(trigger.Commit.Hash), trigger.Commit.Subject)
+		fmt.Printf("           → %s\n", trigger.Rule)
+	}
+	fmt.Println()
+}
@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"esh-cli/pkg/utils"
 	"os"
 	"os/exec"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func TestBumpVersionCmdCreation(t *testing.T) {
@@ -26,7 +28,7 @@ func TestBumpVersionCmdCreation(t *testing.T) {
 
 func TestBumpVersionFlags(t *testing.T) {
 	// Test that flags exist
-	flags := []string{"major", "minor", "patch", "auto", "preview", "service", "from-commit"}
+	flags := []string{"major", "minor", "patch", "auto", "preview", "explain", "graduate", "service", "from-commit", "initial", "seed-from", "train", "remote", "no-fetch"}
 
 	for _, flagName := range flags {
 		flag := bumpVersionCmd.Flags().Lookup(flagName)
@@ -36,7 +38,7 @@ func TestBumpVersionFlags(t *testing.T) {
 	}
 
 	// Test that boolean flags are boolean
-	boolFlags := []string{"major", "minor", "patch", "auto", "preview"}
+	boolFlags := []string{"major", "minor", "patch", "auto", "preview", "explain", "graduate", "train"}
 	for _, flagName := range boolFlags {
 		flag := bumpVersionCmd.Flags().Lookup(flagName)
 		if flag == nil {
@@ -401,3 +403,116 @@ func TestRunBumpVersionWithService(t *testing.T) {
 		})
 	}
 }
+
+func TestRunBumpVersionExplainRequiresAuto(t *testing.T) {
+	if os.Getenv("BE_CRASHER") == "1" {
+		bumpMinor = true
+		bumpExplain = true
+		runBumpVersion(bumpVersionCmd, []string{"stg6"})
+		return
+	}
+
+	subCmd := exec.Command(os.Args[0], "-test.run=TestRunBumpVersionExplainRequiresAuto")
+	subCmd.Env = append(os.Environ(), "BE_CRASHER=1")
+	output, err := subCmd.CombinedOutput()
+	if e, ok := err.(*exec.ExitError); !ok || e.Success() {
+		t.Fatalf("Expected process to exit with error, got %v", err)
+	}
+	if !strings.Contains(string(output), "--explain requires --auto") {
+		t.Errorf("Expected --explain error, got:\n%s", output)
+	}
+}
+
+func TestPrintBumpDecision(t *testing.T) {
+	decision := utils.BumpDecision{
+		Bump:     utils.BumpMajor,
+		Analyzed: 2,
+		Excluded: 1,
+		Triggers: []utils.BumpTrigger{{
+			Commit: utils.CommitMessage{Hash: "0123456789abcdef", Subject: "refactor: split handlers"},
+			Rule:   "BREAKING CHANGE footer",
+			Bump:   utils.BumpMajor,
+		}},
+	}
+
+	output := captureStdout(t, func() { printBumpDecision(decision) })
+	for _, want := range []string{"Bump Decision: major", "2 commits (1 excluded", "01234567 refactor: split handlers", "→ BREAKING CHANGE footer"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("printBumpDecision output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	original := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = original }()
+
+	fn()
+	writer.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(reader); err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	return buf.String()
+}
+
+func TestResolveInitialVersion(t *testing.T) {
+	defer viper.Reset()
+	origInitial, origSeed, origService := bumpInitial, bumpSeedFrom, bumpService
+	defer func() { bumpInitial, bumpSeedFrom, bumpService = origInitial, origSeed, origService }()
+
+	viper.Set("initial_version", "0.1.0")
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "api", "path": "/repo", "initial_version": "1.0.0"},
+	})
+
+	tests := []struct {
+		name       string
+		initial    string
+		seedFrom   string
+		service    string
+		want       string
+		wantSource string
+		shouldErr  bool
+	}{
+		{name: "flag wins", initial: "0.3.0", want: "0.3.0", wantSource: "--initial"},
+		{name: "invalid flag", initial: "0.3", shouldErr: true},
+		{name: "top-level setting", want: "0.1.0", wantSource: "initial_version setting"},
+		{name: "project setting", service: "api", want: "1.0.0", wantSource: "initial_version setting"},
+		{name: "seed from same environment", seedFrom: "dev", shouldErr: true},
+		{name: "seed from unknown environment", seedFrom: "qa", shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bumpInitial, bumpSeedFrom, bumpService = tt.initial, tt.seedFrom, tt.service
+
+			version, source, err := resolveInitialVersion("dev", serviceScope{})
+			if tt.shouldErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q from %q", version, source)
+				}
+				return
+			}
+			if err != nil || version != tt.want || source != tt.wantSource {
+				t.Errorf("resolveInitialVersion() = %q, %q, %v, want %q, %q", version, source, err, tt.want, tt.wantSource)
+			}
+		})
+	}
+
+	viper.Reset()
+	bumpInitial, bumpSeedFrom, bumpService = "", "", ""
+	if version, _, err := resolveInitialVersion("dev", serviceScope{}); version != "" || err != nil {
+		t.Errorf("Expected no initial version without configuration, got %q, %v", version, err)
+	}
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// changelogFormatter renders a changelog in one output format
+type changelogFormatter func(changelog *Changelog) string
+
+// changelogFormatters holds the output formats available to --format
+var changelogFormatters = map[string]changelogFormatter{}
+
+// slackTextLimit is the maximum length of a Block Kit section text
+const slackTextLimit = 3000
+
+// slackHeaderLimit is the maximum number of characters of a Block Kit header
+const slackHeaderLimit = 150
+
+var anchorPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+func init() {
+	registerChangelogFormatter("markdown", formatMarkdown)
+	registerChangelogFormatter("json", formatJSON)
+	registerChangelogFormatter("text", formatText)
+	registerChangelogFormatter("html", formatHTML)
+	registerChangelogFormatter("asciidoc", formatAsciiDoc)
+	registerChangelogFormatter("slack", formatSlack)
+}
+
+// registerChangelogFormatter makes a formatter available under the given format name
+func registerChangelogFormatter(name string, formatter changelogFormatter) {
+	changelogFormatters[name] = formatter
+}
+
+// changelogFormatNames returns the registered format names in alphabetical order
+func changelogFormatNames() []string {
+	names := make([]string, 0, len(changelogFormatters))
+	for name := range changelogFormatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// changelogAnchor converts a section key into an HTML anchor id
+func changelogAnchor(key string) string {
+	anchor := strings.Trim(anchorPattern.ReplaceAllString(strings.ToLower(key), "-"), "-")
+	if anchor == "" {
+		return "section"
+	}
+	return anchor
+}
+
+// shortHash returns the abbreviated form of a commit hash
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+func formatHTML(changelog *Changelog) string {
+	var sb strings.Builder
+	sections := changelogSections(changelog)
+	title := html.EscapeString(changelog.Title)
+
+	sb.WriteString("<!DOCTYPE html>\n")
+	sb.WriteString("<html lang=\"en\">\n<head>\n")
+	sb.WriteString("<meta charset=\"utf-8\">\n")
+	sb.WriteString(fmt.Sprintf("<title>%s</title>\n", title))
+	sb.WriteString("</head>\n<body>\n")
+	sb.WriteString(fmt.Sprintf("<h1 id=\"top\">%s</h1>\n", title))
+
+	if changelog.FromTag != "" && changelog.ToTag != "" {
+		sb.WriteString(fmt.Sprintf("<p><strong>Full Changelog</strong>: <code>%s</code>...<code>%s</code></p>\n",
+			html.EscapeString(changelog.FromTag), html.EscapeString(changelog.ToTag)))
+	}
+
+	// Table of contents linking to every section
+	sb.WriteString("<nav>\n<ul>\n")
+	for _, section := range sections {
+		sb.WriteString(fmt.Sprintf("<li><a href=\"#%s\">%s</a></li>\n",
+			changelogAnchor(section.Key), html.EscapeString(section.Title)))
+	}
+	if len(changelog.Contributors) > 0 {
+		sb.WriteString("<li><a href=\"#contributors\">Contributors</a></li>\n")
+	}
+	if changelog.Stats != nil {
+		sb.WriteString("<li><a href=\"#statistics\">Statistics</a></li>\n")
+	}
+	sb.WriteString("</ul>\n</nav>\n")
+
+	for _, section := range sections {
+		sb.WriteString(fmt.Sprintf("<h2 id=\"%s\">%s</h2>\n", changelogAnchor(section.Key), html.EscapeString(section.Title)))
+		sb.WriteString("<ul>\n")
+		for _, entry := range section.Entries {
+			sb.WriteString("<li>")
+			if entry.Scope != "" {
+				sb.WriteString(fmt.Sprintf("<strong>%s</strong>: ", html.EscapeString(entry.Scope)))
+			}
+			sb.WriteString(html.EscapeString(entry.Description))
+			if entry.Breaking {
+				sb.WriteString(" <strong>BREAKING</strong>")
+			}
+			sb.WriteString(fmt.Sprintf(" (<code id=\"%s\">%s</code>)</li>\n", html.EscapeString(entry.Hash), shortHash(entry.Hash)))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	if len(changelog.Contributors) > 0 {
+		sb.WriteString("<h2 id=\"contributors\">Contributors</h2>\n<ul>\n")
+		for _, contributor := range changelog.Contributors {
+			sb.WriteString(fmt.Sprintf("<li>%s (%s)</li>\n",
+				html.EscapeString(formatContributor(contributor)), pluralize(contributor.Commits, "commit")))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	if changelog.Stats != nil {
+		sb.WriteString("<h2 id=\"statistics\">Statistics</h2>\n<ul>\n")
+		sb.WriteString(fmt.Sprintf("<li>Commits: %d</li>\n", changelog.Stats.Commits))
+		sb.WriteString(fmt.Sprintf("<li>Files changed: %d</li>\n", changelog.Stats.FilesChanged))
+		sb.WriteString(fmt.Sprintf("<li>Insertions: %d</li>\n", changelog.Stats.Insertions))
+		sb.WriteString(fmt.Sprintf("<li>Deletions: %d</li>\n", changelog.Stats.Deletions))
+		sb.WriteString("</ul>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+
+	return sb.String()
+}
+
+func formatAsciiDoc(changelog *Changelog) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("= %s\n\n", changelog.Title))
+
+	if changelog.FromTag != "" && changelog.ToTag != "" {
+		sb.WriteString(fmt.Sprintf("*Full Changelog*: `%s`...`%s`\n\n", changelog.FromTag, changelog.ToTag))
+	}
+
+	for _, section := range changelogSections(changelog) {
+		sb.WriteString(fmt.Sprintf("[[%s]]\n== %s\n\n", changelogAnchor(section.Key), section.Title))
+		for _, entry := range section.Entries {
+			sb.WriteString("* ")
+			if entry.Scope != "" {
+				sb.WriteString(fmt.Sprintf("*%s*: ", entry.Scope))
+			}
+			sb.WriteString(entry.Description)
+			if entry.Breaking {
+				sb.WriteString(" *BREAKING*")
+			}
+			sb.WriteString(fmt.Sprintf(" (`%s`)\n", shortHash(entry.Hash)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(changelog.Contributors) > 0 {
+		sb.WriteString("[[contributors]]\n== Contributors\n\n")
+		for _, contributor := range changelog.Contributors {
+			sb.WriteString(fmt.Sprintf("* %s (%s)\n", formatContributor(contributor), pluralize(contributor.Commits, "commit")))
+		}
+		sb.WriteString("\n")
+	}
+
+	if changelog.Stats != nil {
+		sb.WriteString("[[statistics]]\n== Statistics\n\n")
+		sb.WriteString(fmt.Sprintf("* Commits: %d\n", changelog.Stats.Commits))
+		sb.WriteString(fmt.Sprintf("* Files changed: %d\n", changelog.Stats.FilesChanged))
+		sb.WriteString(fmt.Sprintf("* Insertions: %d\n", changelog.Stats.Insertions))
+		sb.WriteString(fmt.Sprintf("* Deletions: %d\n", changelog.Stats.Deletions))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// slackBlock is a Slack Block Kit layout block
+type slackBlock struct {
+	Type     string       `json:"type"`
+	Text     *slackText   `json:"text,omitempty"`
+	Elements []slackText  `json:"elements,omitempty"`
+	Fields   []*slackText `json:"fields,omitempty"`
+}
+
+// slackText is a Slack Block Kit text object
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// slackEscape escapes the control characters of Slack mrkdwn text
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// truncateSlackText keeps a section text within the Block Kit length limit
+func truncateSlackText(text string) string {
+	if len(text) <= slackTextLimit {
+		return text
+	}
+
+	// Back off to the start of a rune so a multi-byte character is not split
+	const suffix = "\n…"
+	end := slackTextLimit - len(suffix)
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	cut := text[:end]
+	if newline := strings.LastIndex(cut, "\n"); newline > 0 {
+		cut = cut[:newline]
+	}
+	return cut + suffix
+}
+
+// truncateSlackHeader keeps a header text within the Block Kit limit, counted
+// in characters so a multi-byte character is never split
+func truncateSlackHeader(text string) string {
+	runes := []rune(text)
+	if len(runes) <= slackHeaderLimit {
+		return text
+	}
+	return string(runes[:slackHeaderLimit-1]) + "…"
+}
+
+func formatSlack(changelog *Changelog) string {
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: truncateSlackHeader(changelog.Title), Emoji: true}},
+	}
+
+	if changelog.FromTag != "" && changelog.ToTag != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{
+			{Type: "mrkdwn", Text: truncateSlackText(fmt.Sprintf("*Full Changelog*: `%s`...`%s`", slackEscape(changelog.FromTag), slackEscape(changelog.ToTag)))},
+		}})
+	}
+
+	for _, section := range changelogSections(changelog) {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("*%s*\n", slackEscape(section.Title)))
+		for _, entry := range section.Entries {
+			sb.WriteString("• ")
+			if entry.Scope != "" {
+				sb.WriteString(fmt.Sprintf("*%s*: ", slackEscape(entry.Scope)))
+			}
+			sb.WriteString(slackEscape(entry.Description))
+			if entry.Breaking {
+				sb.WriteString(" :warning: *BREAKING*")
+			}
+			sb.WriteString(fmt.Sprintf(" (`%s`)\n", shortHash(entry.Hash)))
+		}
+
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{
+			Type: "mrkdwn",
+			Text: truncateSlackText(strings.TrimSuffix(sb.String(), "\n")),
+		}})
+	}
+
+	if changelog.Stats != nil {
+		blocks = append(blocks, slackBlock{Type: "divider"}, slackBlock{Type: "section", Fields: []*slackText{
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Commits*\n%d", changelog.Stats.Commits)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Files changed*\n%d", changelog.Stats.FilesChanged)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Insertions*\n%d", changelog.Stats.Insertions)},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*Deletions*\n%d", changelog.Stats.Deletions)},
+		}})
+	}
+
+	if len(changelog.Contributors) > 0 {
+		names := make([]string, 0, len(changelog.Contributors))
+		for _, contributor := range changelog.Contributors {
+			name := contributor.Name
+			if name == "" {
+				name = contributor.Email
+			}
+			names = append(names, slackEscape(name))
+		}
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{
+			{Type: "mrkdwn", Text: truncateSlackText("*Contributors*: " + strings.Join(names, ", "))},
+		}})
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Blocks []slackBlock `json:"blocks"`
+	}{blocks}, "", "  ")
+	if err != nil {
+		return "{}\n"
+	}
+
+	return string(data) + "\n"
+}
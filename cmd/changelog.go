@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"esh-cli/pkg/utils"
 	"fmt"
 	"os"
-	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -22,6 +24,9 @@ var (
 	changelogToTag           string
 	changelogGroupByType     bool
 	changelogIncludeBreaking bool
+	changelogContributors    bool
+	changelogStats           bool
+	changelogService         string
 )
 
 // changelogCmd represents the changelog command
@@ -33,8 +38,9 @@ var changelogCmd = &cobra.Command{
 This command analyzes git commits and creates formatted changelogs:
 - Supports conventional commit parsing
 - Groups changes by type (features, fixes, breaking changes)
-- Generates markdown, JSON, or text output
+- Generates markdown, JSON, text, HTML, AsciiDoc or Slack Block Kit output
 - Supports date ranges and tag ranges
+- Optionally lists contributors and release statistics
 
 The changelog can be generated for a specific environment, between two tags,
 or since a specific date.`,
@@ -42,34 +48,53 @@ or since a specific date.`,
   esh-cli changelog --from stg6_1.2.0-1 --to stg6_1.3.0-1  # Between specific tags
   esh-cli changelog --since 2024-01-01            # Changes since date
   esh-cli changelog stg6 --conventional-commits   # Parse conventional commits
-  esh-cli changelog stg6 --format json --output changelog.json`,
+  esh-cli changelog stg6 --format json --output changelog.json
+  esh-cli changelog stg6 --format slack           # Block Kit payload for Slack
+  esh-cli changelog stg6 --contributors --stats   # Credit contributors and show release size
+  esh-cli changelog stg6 --service api            # Only commits touching the api service paths`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runChangelog,
 }
 
 type ChangelogEntry struct {
-	Type        string
-	Scope       string
-	Description string
-	Hash        string
-	Breaking    bool
-	Date        time.Time
+	Type        string    `json:"type"`
+	Scope       string    `json:"scope"`
+	Description string    `json:"description"`
+	Hash        string    `json:"hash"`
+	Breaking    bool      `json:"breaking"`
+	Date        time.Time `json:"date"`
+}
+
+type ChangelogContributor struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+}
+
+type ChangelogStats struct {
+	Commits      int `json:"commits"`
+	FilesChanged int `json:"files_changed"`
+	Insertions   int `json:"insertions"`
+	Deletions    int `json:"deletions"`
 }
 
 type Changelog struct {
-	Title     string
-	FromTag   string
-	ToTag     string
-	FromDate  time.Time
-	ToDate    time.Time
-	Entries   []ChangelogEntry
-	GroupedBy map[string][]ChangelogEntry
+	Title        string
+	FromTag      string
+	ToTag        string
+	FromDate     time.Time
+	ToDate       time.Time
+	Entries      []ChangelogEntry
+	GroupedBy    map[string][]ChangelogEntry
+	Types        []utils.CommitType
+	Contributors []ChangelogContributor
+	Stats        *ChangelogStats
 }
 
 func init() {
 	rootCmd.AddCommand(changelogCmd)
 
-	changelogCmd.Flags().StringVar(&changelogFormat, "format", "markdown", "Output format (markdown, json, text)")
+	changelogCmd.Flags().StringVar(&changelogFormat, "format", "markdown", "Output format (markdown, json, text, html, asciidoc, slack)")
 	changelogCmd.Flags().BoolVar(&changelogConventional, "conventional-commits", false, "Parse conventional commit messages")
 	changelogCmd.Flags().BoolVar(&changelogFull, "full", false, "Generate complete changelog")
 	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Changes since date (YYYY-MM-DD)")
@@ -78,6 +103,9 @@ func init() {
 	changelogCmd.Flags().StringVar(&changelogToTag, "to", "", "End tag for range")
 	changelogCmd.Flags().BoolVar(&changelogGroupByType, "group-by-type", true, "Group entries by type")
 	changelogCmd.Flags().BoolVar(&changelogIncludeBreaking, "include-breaking", true, "Include breaking changes section")
+	changelogCmd.Flags().BoolVar(&changelogContributors, "contributors", false, "Include contributors (with co-authors)")
+	changelogCmd.Flags().BoolVar(&changelogStats, "stats", false, "Include commit, file and line statistics")
+	changelogCmd.Flags().StringVarP(&changelogService, "service", "s", "", "Limit the changelog to a service's repository and paths")
 }
 
 func runChangelog(cmd *cobra.Command, args []string) {
@@ -91,13 +119,19 @@ func runChangelog(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	scope, err := resolveServiceScope(changelogService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Determine tag range
 	fromTag := changelogFromTag
 	toTag := changelogToTag
 
 	if environment != "" && fromTag == "" && toTag == "" {
 		// Get latest and previous tag for environment
-		latest, err := getLatestTagForEnvironment(environment)
+		latest, err := getLatestTagForEnvironment(environment, scope)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error finding latest tag: %v\n", err)
 			os.Exit(1)
@@ -105,33 +139,34 @@ func runChangelog(cmd *cobra.Command, args []string) {
 		toTag = latest
 
 		if !changelogFull {
-			previous, err := findPreviousTag(latest)
+			previous, err := findPreviousTag(latest, scope, "")
 			if err == nil {
 				fromTag = previous
 			}
 		}
 	}
 
+	taxonomy, err := loadCommitTaxonomy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading changelog configuration: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Generate changelog
-	changelog, err := generateChangelog(fromTag, toTag, environment)
+	changelog, err := generateChangelog(fromTag, toTag, environment, taxonomy, scope)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error generating changelog: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Format output
-	var output string
-	switch changelogFormat {
-	case "markdown":
-		output = formatMarkdown(changelog)
-	case "json":
-		output = formatJSON(changelog)
-	case "text":
-		output = formatText(changelog)
-	default:
-		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s'. Use: markdown, json, text\n", changelogFormat)
+	formatter, exists := changelogFormatters[changelogFormat]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format '%s'. Use: %s\n",
+			changelogFormat, strings.Join(changelogFormatNames(), ", "))
 		os.Exit(1)
 	}
+	output := formatter(changelog)
 
 	// Write output
 	if changelogOutput != "" {
@@ -146,13 +181,34 @@ func runChangelog(cmd *cobra.Command, args []string) {
 	}
 }
 
-func generateChangelog(fromTag, toTag, environment string) (*Changelog, error) {
+// loadCommitTaxonomy builds the commit taxonomy from the changelog section of the config
+func loadCommitTaxonomy() (*utils.CommitTaxonomy, error) {
+	var types []utils.CommitType
+	if err := viper.UnmarshalKey("changelog.types", &types); err != nil {
+		return nil, fmt.Errorf("invalid changelog.types: %v", err)
+	}
+
+	var exclude []string
+	if viper.IsSet("changelog.exclude") {
+		exclude = viper.GetStringSlice("changelog.exclude")
+	}
+
+	return utils.NewCommitTaxonomy(types, exclude)
+}
+
+func generateChangelog(fromTag, toTag, environment string, taxonomy *utils.CommitTaxonomy, scope serviceScope) (*Changelog, error) {
 	changelog := &Changelog{
 		FromTag:   fromTag,
 		ToTag:     toTag,
 		GroupedBy: make(map[string][]ChangelogEntry),
 	}
 
+	for _, commitType := range taxonomy.Types {
+		if !commitType.Hidden {
+			changelog.Types = append(changelog.Types, commitType)
+		}
+	}
+
 	// Set title
 	if environment != "" {
 		if fromTag != "" && toTag != "" {
@@ -169,59 +225,33 @@ func generateChangelog(fromTag, toTag, environment string) (*Changelog, error) {
 	}
 
 	// Get commits
-	var commits []string
-	var err error
-
-	if fromTag != "" && toTag != "" {
-		commits, err = utils.GetCommitsBetweenTags(fromTag, toTag)
-	} else if toTag != "" {
-		// Get all commits up to toTag
-		output, e := utils.Cmd(fmt.Sprintf("git log --oneline --pretty=format:\"%%H %%s\" %s", toTag))
-		if e != nil {
-			err = e
-		} else if output != "" {
-			commits = strings.Split(output, "\n")
-		}
-	} else if changelogSince != "" {
-		// Get commits since date
-		output, e := utils.Cmd(fmt.Sprintf("git log --oneline --pretty=format:\"%%H %%s\" --since=\"%s\"", changelogSince))
-		if e != nil {
-			err = e
-		} else if output != "" {
-			commits = strings.Split(output, "\n")
-		}
-	} else {
-		// Get recent commits
-		output, e := utils.Cmd("git log --oneline --pretty=format:\"%H %s\" -20")
-		if e != nil {
-			err = e
-		} else if output != "" {
-			commits = strings.Split(output, "\n")
-		}
-	}
+	logArgs := changelogLogArgs(fromTag, toTag) + scope.pathspec()
 
+	// Subjects and bodies, so breaking change footers are seen
+	commits, err := utils.GetCommitMessagesInDir(changelogLogArgs(fromTag, toTag), scope.Dir, scope.Paths)
 	if err != nil {
 		return nil, fmt.Errorf("error getting commits: %v", err)
 	}
 
 	// Parse commits into changelog entries
 	for _, commit := range commits {
-		if commit == "" {
+		entry := parseCommit(commit, taxonomy)
+		if entry == nil {
+			continue
+		}
+		entry.Date = getCommitDate(entry.Hash, scope)
+
+		// Hidden types are left out unless they carry a breaking change
+		if taxonomy.Lookup(entry.Type).Hidden && !entry.Breaking {
 			continue
 		}
 
-		entry := parseCommit(commit)
-		if entry != nil {
-			changelog.Entries = append(changelog.Entries, *entry)
+		changelog.Entries = append(changelog.Entries, *entry)
 
-			// Group by type
-			if changelogGroupByType {
-				typeKey := entry.Type
-				if typeKey == "" {
-					typeKey = "Other"
-				}
-				changelog.GroupedBy[typeKey] = append(changelog.GroupedBy[typeKey], *entry)
-			}
+		// Group by type
+		if changelogGroupByType {
+			typeKey := taxonomy.Lookup(entry.Type).Name
+			changelog.GroupedBy[typeKey] = append(changelog.GroupedBy[typeKey], *entry)
 		}
 	}
 
@@ -230,90 +260,223 @@ func generateChangelog(fromTag, toTag, environment string) (*Changelog, error) {
 		return changelog.Entries[i].Date.After(changelog.Entries[j].Date)
 	})
 
+	if changelogContributors {
+		changelog.Contributors, err = getChangelogContributors(logArgs, scope)
+		if err != nil {
+			return nil, fmt.Errorf("error getting contributors: %v", err)
+		}
+	}
+
+	if changelogStats {
+		changelog.Stats, err = getChangelogStats(logArgs, scope)
+		if err != nil {
+			return nil, fmt.Errorf("error getting statistics: %v", err)
+		}
+	}
+
 	return changelog, nil
 }
 
-func parseCommit(commit string) *ChangelogEntry {
-	parts := strings.SplitN(commit, " ", 2)
-	if len(parts) != 2 {
+// changelogLogArgs returns the git log arguments selecting the changelog commits
+func changelogLogArgs(fromTag, toTag string) string {
+	if fromTag != "" && toTag != "" {
+		return fmt.Sprintf("%s..%s", fromTag, toTag)
+	} else if toTag != "" {
+		// All commits up to toTag
+		return toTag
+	} else if changelogSince != "" {
+		// Commits since date
+		return fmt.Sprintf("--since=\"%s\"", changelogSince)
+	}
+
+	// Recent commits
+	return "-20"
+}
+
+// getChangelogContributors collects commit authors and co-authors for the changelog commits
+func getChangelogContributors(logArgs string, scope serviceScope) ([]ChangelogContributor, error) {
+	output, err := scope.git(fmt.Sprintf(
+		"git log --pretty=format:\"%%aN%%x1f%%aE%%x1f%%(trailers:key=Co-authored-by,valueonly,separator=%%x1d)%%x1e\" %s", logArgs))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseContributors(output), nil
+}
+
+// parseContributors parses author records separated by \x1e, with name, email and
+// co-authored-by trailers separated by \x1f and trailers separated by \x1d
+func parseContributors(output string) []ChangelogContributor {
+	counts := make(map[string]*ChangelogContributor)
+	var order []string
+
+	add := func(name, email string) {
+		name = strings.TrimSpace(name)
+		email = strings.TrimSpace(email)
+		if name == "" && email == "" {
+			return
+		}
+
+		key := strings.ToLower(email)
+		if key == "" {
+			key = strings.ToLower(name)
+		}
+
+		if contributor, exists := counts[key]; exists {
+			contributor.Commits++
+			return
+		}
+		counts[key] = &ChangelogContributor{Name: name, Email: email, Commits: 1}
+		order = append(order, key)
+	}
+
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.Trim(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, "\x1f")
+		if len(fields) < 2 {
+			continue
+		}
+		add(fields[0], fields[1])
+
+		if len(fields) > 2 {
+			for _, trailer := range strings.Split(fields[2], "\x1d") {
+				name, email := parseCoAuthor(trailer)
+				add(name, email)
+			}
+		}
+	}
+
+	contributors := make([]ChangelogContributor, 0, len(order))
+	for _, key := range order {
+		contributors = append(contributors, *counts[key])
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		if contributors[i].Commits != contributors[j].Commits {
+			return contributors[i].Commits > contributors[j].Commits
+		}
+		return strings.ToLower(contributors[i].Name) < strings.ToLower(contributors[j].Name)
+	})
+
+	return contributors
+}
+
+// parseCoAuthor splits a "Name <email>" co-authored-by trailer value
+func parseCoAuthor(trailer string) (string, string) {
+	trailer = strings.TrimSpace(trailer)
+	start := strings.LastIndex(trailer, "<")
+	end := strings.LastIndex(trailer, ">")
+	if start == -1 || end < start {
+		return trailer, ""
+	}
+	return strings.TrimSpace(trailer[:start]), strings.TrimSpace(trailer[start+1 : end])
+}
+
+// getChangelogStats sums commit count and line changes for the changelog commits
+func getChangelogStats(logArgs string, scope serviceScope) (*ChangelogStats, error) {
+	output, err := scope.git(fmt.Sprintf("git log --numstat --pretty=format:\"commit %%H\" %s", logArgs))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNumstat(output), nil
+}
+
+// parseNumstat aggregates `git log --numstat` output into changelog statistics
+func parseNumstat(output string) *ChangelogStats {
+	stats := &ChangelogStats{}
+	files := make(map[string]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "commit ") {
+			stats.Commits++
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		// Binary files report "-" for line counts
+		if added, err := strconv.Atoi(fields[0]); err == nil {
+			stats.Insertions += added
+		}
+		if deleted, err := strconv.Atoi(fields[1]); err == nil {
+			stats.Deletions += deleted
+		}
+		files[fields[2]] = true
+	}
+
+	stats.FilesChanged = len(files)
+	return stats
+}
+
+func parseCommit(commit utils.CommitMessage, taxonomy *utils.CommitTaxonomy) *ChangelogEntry {
+	hash := commit.Hash
+	message := commit.Subject
+	if hash == "" || message == "" {
 		return nil
 	}
 
-	hash := parts[0]
-	message := parts[1]
+	if taxonomy.IsExcluded(message) {
+		return nil
+	}
 
 	entry := &ChangelogEntry{
 		Hash:        hash,
 		Description: message,
-		Date:        getCommitDate(hash),
 	}
 
 	if changelogConventional {
-		parseConventionalCommit(entry, message)
+		parseConventionalCommit(entry, commit, taxonomy)
 	} else {
 		// Simple parsing - try to detect type from message
-		entry.Type = detectCommitType(message)
+		entry.Type = detectCommitType(message, taxonomy)
 	}
 
 	return entry
 }
 
-func parseConventionalCommit(entry *ChangelogEntry, message string) {
+func parseConventionalCommit(entry *ChangelogEntry, commit utils.CommitMessage, taxonomy *utils.CommitTaxonomy) {
+	message := commit.Subject
+
 	// Conventional commit format: type(scope): description
 	// Optional: type(scope)!: description (breaking change)
-	conventionalRegex := regexp.MustCompile(`^(\w+)(\([^)]+\))?(!)?: (.+)$`)
-	matches := conventionalRegex.FindStringSubmatch(message)
+	matches := utils.ConventionalCommitPattern.FindStringSubmatch(message)
 
 	if len(matches) >= 5 {
-		entry.Type = matches[1]
+		entry.Type = taxonomy.Lookup(matches[1]).Name
 		if matches[2] != "" {
 			// Remove parentheses from scope
 			entry.Scope = strings.Trim(matches[2], "()")
 		}
-		entry.Breaking = matches[3] == "!"
 		entry.Description = matches[4]
 	} else {
 		// Fallback to simple type detection
-		entry.Type = detectCommitType(message)
+		entry.Type = detectCommitType(message, taxonomy)
 		entry.Description = message
 	}
 
-	// Check for BREAKING CHANGE in message body
-	if strings.Contains(strings.ToUpper(message), "BREAKING CHANGE") {
-		entry.Breaking = true
-	}
+	// A '!' header or a BREAKING CHANGE footer, as for the version bump
+	entry.Breaking = commit.IsBreaking()
 }
 
-func detectCommitType(message string) string {
-	messageLower := strings.ToLower(message)
-
-	if strings.HasPrefix(messageLower, "feat") || strings.Contains(messageLower, "feature") || strings.Contains(messageLower, "add ") {
-		return "feat"
-	}
-	if strings.HasPrefix(messageLower, "fix") || strings.Contains(messageLower, "bug") || strings.Contains(messageLower, "issue") {
-		return "fix"
-	}
-	if strings.Contains(messageLower, "doc") || strings.Contains(messageLower, "readme") {
-		return "docs"
-	}
-	if strings.Contains(messageLower, "style") || strings.Contains(messageLower, "format") {
-		return "style"
-	}
-	if strings.Contains(messageLower, "refactor") || strings.Contains(messageLower, "cleanup") {
-		return "refactor"
-	}
-	if strings.Contains(messageLower, "test") {
-		return "test"
-	}
-	if strings.Contains(messageLower, "chore") || strings.Contains(messageLower, "update") {
-		return "chore"
-	}
-
-	return "other"
+func detectCommitType(message string, taxonomy *utils.CommitTaxonomy) string {
+	return taxonomy.Classify(message)
 }
 
-func getCommitDate(hash string) time.Time {
-	output, err := utils.Cmd(fmt.Sprintf("git show -s --format=%%ci %s", hash))
+func getCommitDate(hash string, scope serviceScope) time.Time {
+	output, err := scope.git(fmt.Sprintf("git show -s --format=%%ci %s", hash))
 	if err != nil {
 		return time.Time{}
 	}
@@ -335,59 +498,97 @@ func formatMarkdown(changelog *Changelog) string {
 		sb.WriteString(fmt.Sprintf("**Full Changelog**: %s...%s\n\n", changelog.FromTag, changelog.ToTag))
 	}
 
-	if changelogGroupByType && len(changelog.GroupedBy) > 0 {
-		// Define order for sections
-		sectionOrder := []string{"feat", "fix", "perf", "refactor", "docs", "style", "test", "chore", "other"}
-		sectionTitles := map[string]string{
-			"feat":     "🚀 Features",
-			"fix":      "🐛 Bug Fixes",
-			"perf":     "⚡ Performance",
-			"refactor": "♻️ Refactoring",
-			"docs":     "📚 Documentation",
-			"style":    "💄 Style",
-			"test":     "🧪 Tests",
-			"chore":    "🔧 Chores",
-			"other":    "📝 Other Changes",
-		}
-
-		// Add breaking changes first if any
-		if changelogIncludeBreaking {
-			breakingChanges := getBreakingChanges(changelog.Entries)
-			if len(breakingChanges) > 0 {
-				sb.WriteString("## 💥 Breaking Changes\n\n")
-				for _, entry := range breakingChanges {
-					sb.WriteString(formatMarkdownEntry(entry))
-				}
-				sb.WriteString("\n")
-			}
+	for _, section := range changelogSections(changelog) {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", section.Title))
+		for _, entry := range section.Entries {
+			sb.WriteString(formatMarkdownEntry(entry))
 		}
+		sb.WriteString("\n")
+	}
 
-		// Add sections in order
-		for _, sectionKey := range sectionOrder {
-			if entries, exists := changelog.GroupedBy[sectionKey]; exists && len(entries) > 0 {
-				title := sectionTitles[sectionKey]
-				if title == "" {
-					title = strings.Title(sectionKey)
-				}
-
-				sb.WriteString(fmt.Sprintf("## %s\n\n", title))
-				for _, entry := range entries {
-					if !entry.Breaking || !changelogIncludeBreaking {
-						sb.WriteString(formatMarkdownEntry(entry))
-					}
-				}
-				sb.WriteString("\n")
+	if len(changelog.Contributors) > 0 {
+		sb.WriteString("## 👥 Contributors\n\n")
+		for _, contributor := range changelog.Contributors {
+			sb.WriteString(fmt.Sprintf("- %s (%s)\n", formatContributor(contributor), pluralize(contributor.Commits, "commit")))
+		}
+		sb.WriteString("\n")
+	}
+
+	if changelog.Stats != nil {
+		sb.WriteString("## 📊 Statistics\n\n")
+		sb.WriteString(fmt.Sprintf("- Commits: %d\n", changelog.Stats.Commits))
+		sb.WriteString(fmt.Sprintf("- Files changed: %d\n", changelog.Stats.FilesChanged))
+		sb.WriteString(fmt.Sprintf("- Insertions: %d\n", changelog.Stats.Insertions))
+		sb.WriteString(fmt.Sprintf("- Deletions: %d\n", changelog.Stats.Deletions))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// formatContributor renders a contributor as "Name <email>"
+func formatContributor(contributor ChangelogContributor) string {
+	if contributor.Email == "" {
+		return contributor.Name
+	}
+	if contributor.Name == "" {
+		return contributor.Email
+	}
+	return fmt.Sprintf("%s <%s>", contributor.Name, contributor.Email)
+}
+
+// pluralize formats a count with a singular or plural noun
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// changelogSection is a titled group of changelog entries in output order
+type changelogSection struct {
+	Key     string
+	Title   string
+	Entries []ChangelogEntry
+}
+
+// changelogSections groups the changelog entries for rendering: breaking
+// changes first, then one section per visible type in the configured order,
+// or a single "Changes" section when grouping is disabled
+func changelogSections(changelog *Changelog) []changelogSection {
+	if !changelogGroupByType || len(changelog.GroupedBy) == 0 {
+		return []changelogSection{{Key: "changes", Title: "Changes", Entries: changelog.Entries}}
+	}
+
+	var sections []changelogSection
+
+	// Add breaking changes first if any
+	if changelogIncludeBreaking {
+		if breakingChanges := getBreakingChanges(changelog.Entries); len(breakingChanges) > 0 {
+			sections = append(sections, changelogSection{Key: "breaking", Title: "💥 Breaking Changes", Entries: breakingChanges})
+		}
+	}
+
+	// Add sections in the configured type order
+	for _, commitType := range changelog.Types {
+		var entries []ChangelogEntry
+		for _, entry := range changelog.GroupedBy[commitType.Name] {
+			if !entry.Breaking || !changelogIncludeBreaking {
+				entries = append(entries, entry)
 			}
 		}
-	} else {
-		// Simple list format
-		sb.WriteString("## Changes\n\n")
-		for _, entry := range changelog.Entries {
-			sb.WriteString(formatMarkdownEntry(entry))
+		if len(entries) == 0 {
+			continue
 		}
+
+		title := commitType.Title
+		if title == "" {
+			title = strings.ToUpper(commitType.Name[:1]) + commitType.Name[1:]
+		}
+		sections = append(sections, changelogSection{Key: commitType.Name, Title: title, Entries: entries})
 	}
 
-	return sb.String()
+	return sections
 }
 
 func formatMarkdownEntry(entry ChangelogEntry) string {
@@ -411,34 +612,33 @@ func formatMarkdownEntry(entry ChangelogEntry) string {
 }
 
 func formatJSON(changelog *Changelog) string {
-	// Simple JSON format - in a real implementation you'd use json.Marshal
-	var sb strings.Builder
-
-	sb.WriteString("{\n")
-	sb.WriteString(fmt.Sprintf("  \"title\": \"%s\",\n", changelog.Title))
-	sb.WriteString(fmt.Sprintf("  \"from_tag\": \"%s\",\n", changelog.FromTag))
-	sb.WriteString(fmt.Sprintf("  \"to_tag\": \"%s\",\n", changelog.ToTag))
-	sb.WriteString("  \"entries\": [\n")
-
-	for i, entry := range changelog.Entries {
-		sb.WriteString("    {\n")
-		sb.WriteString(fmt.Sprintf("      \"type\": \"%s\",\n", entry.Type))
-		sb.WriteString(fmt.Sprintf("      \"scope\": \"%s\",\n", entry.Scope))
-		sb.WriteString(fmt.Sprintf("      \"description\": \"%s\",\n", entry.Description))
-		sb.WriteString(fmt.Sprintf("      \"hash\": \"%s\",\n", entry.Hash))
-		sb.WriteString(fmt.Sprintf("      \"breaking\": %t,\n", entry.Breaking))
-		sb.WriteString(fmt.Sprintf("      \"date\": \"%s\"\n", entry.Date.Format(time.RFC3339)))
-		if i < len(changelog.Entries)-1 {
-			sb.WriteString("    },\n")
-		} else {
-			sb.WriteString("    }\n")
-		}
+	entries := changelog.Entries
+	if entries == nil {
+		entries = []ChangelogEntry{}
+	}
+
+	document := struct {
+		Title        string                 `json:"title"`
+		FromTag      string                 `json:"from_tag"`
+		ToTag        string                 `json:"to_tag"`
+		Entries      []ChangelogEntry       `json:"entries"`
+		Contributors []ChangelogContributor `json:"contributors,omitempty"`
+		Stats        *ChangelogStats        `json:"stats,omitempty"`
+	}{
+		Title:        changelog.Title,
+		FromTag:      changelog.FromTag,
+		ToTag:        changelog.ToTag,
+		Entries:      entries,
+		Contributors: changelog.Contributors,
+		Stats:        changelog.Stats,
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "{}\n"
 	}
 
-	sb.WriteString("  ]\n")
-	sb.WriteString("}\n")
-
-	return sb.String()
+	return string(data) + "\n"
 }
 
 func formatText(changelog *Changelog) string {
@@ -462,21 +662,30 @@ func formatText(changelog *Changelog) string {
 		sb.WriteString(fmt.Sprintf(" [%s]\n", entry.Hash[:8]))
 	}
 
+	if len(changelog.Contributors) > 0 {
+		sb.WriteString("\nContributors:\n")
+		for _, contributor := range changelog.Contributors {
+			sb.WriteString(fmt.Sprintf("  %s (%s)\n", formatContributor(contributor), pluralize(contributor.Commits, "commit")))
+		}
+	}
+
+	if changelog.Stats != nil {
+		sb.WriteString("\nStatistics:\n")
+		sb.WriteString(fmt.Sprintf("  Commits: %d\n", changelog.Stats.Commits))
+		sb.WriteString(fmt.Sprintf("  Files changed: %d\n", changelog.Stats.FilesChanged))
+		sb.WriteString(fmt.Sprintf("  Insertions: %d\n", changelog.Stats.Insertions))
+		sb.WriteString(fmt.Sprintf("  Deletions: %d\n", changelog.Stats.Deletions))
+	}
+
 	return sb.String()
 }
 
-func getLatestTagForEnvironment(environment string) (string, error) {
-	pattern := fmt.Sprintf("*_%s_*", environment)
-	output, err := utils.Cmd(fmt.Sprintf("git tag -l '%s' --sort=-version:refname", pattern))
+func getLatestTagForEnvironment(environment string, scope serviceScope) (string, error) {
+	tags, err := utils.ListEnvironmentTags(environment, scope.Name, scope.Dir)
 	if err != nil {
 		return "", err
 	}
 
-	if output == "" {
-		return "", fmt.Errorf("no tags found for environment: %s", environment)
-	}
-
-	tags := strings.Split(output, "\n")
 	if len(tags) == 0 {
 		return "", fmt.Errorf("no tags found for environment: %s", environment)
 	}
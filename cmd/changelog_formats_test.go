@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"esh-cli/pkg/utils"
+)
+
+func testFormatsChangelog() *Changelog {
+	feat := ChangelogEntry{Type: "feat", Scope: "api", Description: "add <login> & logout", Hash: "aaaaaaaaaaaaaaaa"}
+	fix := ChangelogEntry{Type: "fix", Description: "drop v1 routes", Breaking: true, Hash: "bbbbbbbbbbbbbbbb"}
+
+	return &Changelog{
+		Title:   "Changelog",
+		FromTag: "stg6_1.0.0-1",
+		ToTag:   "stg6_1.1.0-1",
+		Entries: []ChangelogEntry{feat, fix},
+		GroupedBy: map[string][]ChangelogEntry{
+			"feat": {feat},
+			"fix":  {fix},
+		},
+		Types:        utils.DefaultCommitTaxonomy().Types,
+		Contributors: []ChangelogContributor{{Name: "Alice", Email: "alice@example.com", Commits: 2}},
+		Stats:        &ChangelogStats{Commits: 2, FilesChanged: 3, Insertions: 10, Deletions: 4},
+	}
+}
+
+func TestChangelogFormatterRegistry(t *testing.T) {
+	for _, name := range []string{"markdown", "json", "text", "html", "asciidoc", "slack"} {
+		if _, exists := changelogFormatters[name]; !exists {
+			t.Errorf("Expected formatter %q to be registered", name)
+		}
+	}
+
+	names := changelogFormatNames()
+	if len(names) != len(changelogFormatters) || names[0] != "asciidoc" {
+		t.Errorf("changelogFormatNames() = %v, want sorted registry names", names)
+	}
+}
+
+func TestChangelogSections(t *testing.T) {
+	origGroup, origBreaking := changelogGroupByType, changelogIncludeBreaking
+	defer func() { changelogGroupByType, changelogIncludeBreaking = origGroup, origBreaking }()
+
+	changelogGroupByType, changelogIncludeBreaking = true, true
+	sections := changelogSections(testFormatsChangelog())
+	if len(sections) != 2 || sections[0].Key != "breaking" || sections[1].Key != "feat" {
+		t.Errorf("Expected breaking then feat sections, got %+v", sections)
+	}
+
+	changelogGroupByType = false
+	sections = changelogSections(testFormatsChangelog())
+	if len(sections) != 1 || sections[0].Title != "Changes" || len(sections[0].Entries) != 2 {
+		t.Errorf("Expected a single Changes section, got %+v", sections)
+	}
+}
+
+func TestFormatHTML(t *testing.T) {
+	origGroup, origBreaking := changelogGroupByType, changelogIncludeBreaking
+	defer func() { changelogGroupByType, changelogIncludeBreaking = origGroup, origBreaking }()
+	changelogGroupByType, changelogIncludeBreaking = true, true
+
+	output := formatHTML(testFormatsChangelog())
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<a href=\"#breaking\">",
+		"<h2 id=\"feat\">",
+		"add &lt;login&gt; &amp; logout",
+		"<h2 id=\"contributors\">Contributors</h2>",
+		"<li>Insertions: 10</li>",
+		"</html>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("HTML output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestFormatAsciiDoc(t *testing.T) {
+	origGroup, origBreaking := changelogGroupByType, changelogIncludeBreaking
+	defer func() { changelogGroupByType, changelogIncludeBreaking = origGroup, origBreaking }()
+	changelogGroupByType, changelogIncludeBreaking = true, true
+
+	output := formatAsciiDoc(testFormatsChangelog())
+	for _, want := range []string{
+		"= Changelog\n",
+		"[[feat]]\n== 🚀 Features",
+		"* *api*: add <login> & logout (`aaaaaaaa`)",
+		"drop v1 routes *BREAKING*",
+		"== Statistics",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("AsciiDoc output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestFormatSlack(t *testing.T) {
+	origGroup, origBreaking := changelogGroupByType, changelogIncludeBreaking
+	defer func() { changelogGroupByType, changelogIncludeBreaking = origGroup, origBreaking }()
+	changelogGroupByType, changelogIncludeBreaking = true, true
+
+	var payload struct {
+		Blocks []slackBlock `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(formatSlack(testFormatsChangelog())), &payload); err != nil {
+		t.Fatalf("formatSlack produced invalid JSON: %v", err)
+	}
+
+	var types []string
+	for _, block := range payload.Blocks {
+		types = append(types, block.Type)
+	}
+	want := "header,context,section,section,divider,section,context"
+	if got := strings.Join(types, ","); got != want {
+		t.Errorf("Slack block types = %s, want %s", got, want)
+	}
+
+	if text := payload.Blocks[3].Text.Text; !strings.Contains(text, "add &lt;login&gt; &amp; logout") {
+		t.Errorf("Expected escaped mrkdwn in feature section, got %q", text)
+	}
+}
+
+func TestTruncateSlackText(t *testing.T) {
+	short := "• one\n• two"
+	if got := truncateSlackText(short); got != short {
+		t.Errorf("truncateSlackText() changed short text: %q", got)
+	}
+
+	long := strings.Repeat("• a commit description\n", 500)
+	got := truncateSlackText(long)
+	if len(got) > slackTextLimit || !strings.HasSuffix(got, "…") {
+		t.Errorf("truncateSlackText() returned %d bytes, want at most %d ending in ellipsis", len(got), slackTextLimit)
+	}
+
+	// A single line of multi-byte characters is cut between characters
+	wide := strings.Repeat("日本", slackTextLimit)
+	got = truncateSlackText(wide)
+	if len(got) > slackTextLimit || !utf8.ValidString(got) || !strings.HasSuffix(got, "…") {
+		t.Errorf("truncateSlackText() returned %d bytes (valid UTF-8: %v), want at most %d ending in ellipsis",
+			len(got), utf8.ValidString(got), slackTextLimit)
+	}
+}
+
+func TestTruncateSlackHeader(t *testing.T) {
+	short := "Changelog for stg6"
+	if got := truncateSlackHeader(short); got != short {
+		t.Errorf("truncateSlackHeader() changed short header: %q", got)
+	}
+
+	// The limit counts characters, not bytes
+	exact := strings.Repeat("日", slackHeaderLimit)
+	if got := truncateSlackHeader(exact); got != exact {
+		t.Errorf("truncateSlackHeader() changed a header of exactly %d characters", slackHeaderLimit)
+	}
+
+	got := truncateSlackHeader(strings.Repeat("日本", slackHeaderLimit))
+	if utf8.RuneCountInString(got) != slackHeaderLimit || !utf8.ValidString(got) || !strings.HasSuffix(got, "…") {
+		t.Errorf("truncateSlackHeader() returned %d characters (valid UTF-8: %v), want %d ending in ellipsis",
+			utf8.RuneCountInString(got), utf8.ValidString(got), slackHeaderLimit)
+	}
+
+	changelog := testFormatsChangelog()
+	changelog.Title = strings.Repeat("release ", 40)
+	changelog.FromTag = strings.Repeat("a", slackTextLimit)
+	var payload struct {
+		Blocks []struct {
+			Text     *struct{ Text string }  `json:"text"`
+			Elements []struct{ Text string } `json:"elements"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal([]byte(formatSlack(changelog)), &payload); err != nil {
+		t.Fatalf("formatSlack produced invalid JSON: %v", err)
+	}
+	if header := payload.Blocks[0].Text.Text; utf8.RuneCountInString(header) > slackHeaderLimit {
+		t.Errorf("header has %d characters, want at most %d", utf8.RuneCountInString(header), slackHeaderLimit)
+	}
+	if context := payload.Blocks[1].Elements[0].Text; len(context) > slackTextLimit {
+		t.Errorf("Full Changelog context has %d bytes, want at most %d", len(context), slackTextLimit)
+	}
+}
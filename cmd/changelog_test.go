@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"esh-cli/pkg/utils"
 	"strings"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestChangelogCmdCreation(t *testing.T) {
@@ -23,7 +26,7 @@ func TestChangelogCmdCreation(t *testing.T) {
 
 func TestChangelogFlags(t *testing.T) {
 	// Test that flags exist
-	flags := []string{"format", "from", "to", "since", "output"}
+	flags := []string{"format", "from", "to", "since", "output", "service"}
 
 	for _, flagName := range flags {
 		flag := changelogCmd.Flags().Lookup(flagName)
@@ -33,7 +36,7 @@ func TestChangelogFlags(t *testing.T) {
 	}
 
 	// Test boolean flags
-	boolFlags := []string{"conventional-commits", "full", "group-by-type", "include-breaking"}
+	boolFlags := []string{"conventional-commits", "full", "group-by-type", "include-breaking", "contributors", "stats"}
 	for _, flagName := range boolFlags {
 		flag := changelogCmd.Flags().Lookup(flagName)
 		if flag == nil {
@@ -286,3 +289,203 @@ func TestParseCommitType(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadCommitTaxonomy(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("changelog.types", []interface{}{
+		map[string]interface{}{
+			"name":    "fix",
+			"title":   "Fixes",
+			"order":   1,
+			"aliases": []interface{}{"bugfix", "patch"},
+		},
+		map[string]interface{}{
+			"name":   "chore",
+			"hidden": true,
+		},
+	})
+	viper.Set("changelog.exclude", []interface{}{"^WIP"})
+
+	taxonomy, err := loadCommitTaxonomy()
+	if err != nil {
+		t.Fatalf("loadCommitTaxonomy unexpected error: %v", err)
+	}
+
+	if got := taxonomy.Lookup("patch"); got.Title != "Fixes" {
+		t.Errorf("Expected alias 'patch' to resolve to configured fix type, got %+v", got)
+	}
+	if !taxonomy.Lookup("chore").Hidden {
+		t.Error("Expected chore to be hidden")
+	}
+	if !taxonomy.IsExcluded("WIP: something") {
+		t.Error("Expected configured exclusion to apply")
+	}
+
+	viper.Set("changelog.types", "invalid")
+	if _, err := loadCommitTaxonomy(); err == nil {
+		t.Error("Expected error for invalid changelog.types")
+	}
+}
+
+func TestParseCommitWithTaxonomy(t *testing.T) {
+	taxonomy := utils.DefaultCommitTaxonomy()
+
+	origConventional := changelogConventional
+	defer func() { changelogConventional = origConventional }()
+
+	changelogConventional = true
+	entry := parseCommit(utils.ParseCommitMessage("0123456789abcdef", "feature(api)!: new endpoint"), taxonomy)
+	if entry == nil {
+		t.Fatal("Expected entry for conventional commit")
+	}
+	if entry.Type != "feat" || entry.Scope != "api" || !entry.Breaking {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+
+	if entry := parseCommit(utils.ParseCommitMessage("0123456789abcdef", "Merge branch 'main' into dev"), taxonomy); entry != nil {
+		t.Errorf("Expected merge commit to be excluded, got %+v", entry)
+	}
+
+	entry = parseCommit(utils.ParseCommitMessage("0123456789abcdef", "feat: new endpoint\n\nBREAKING CHANGE: v1 is removed"), taxonomy)
+	if entry == nil || !entry.Breaking || entry.Description != "new endpoint" {
+		t.Errorf("Expected a BREAKING CHANGE footer to mark the entry breaking, got %+v", entry)
+	}
+
+	entry = parseCommit(utils.ParseCommitMessage("0123456789abcdef", "fix: handle breaking change detection"), taxonomy)
+	if entry == nil || entry.Breaking {
+		t.Errorf("Expected 'breaking change' in the subject not to mark the entry breaking, got %+v", entry)
+	}
+
+	changelogConventional = false
+	entry = parseCommit(utils.ParseCommitMessage("0123456789abcdef", "Update issue template"), taxonomy)
+	if entry == nil || entry.Type != utils.OtherCommitType {
+		t.Errorf("Expected 'other' type for non-conventional update commit, got %+v", entry)
+	}
+}
+
+func TestFormatMarkdownTypeOrder(t *testing.T) {
+	taxonomy, err := utils.NewCommitTaxonomy([]utils.CommitType{
+		{Name: "fix", Title: "Fixes", Order: 1},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewCommitTaxonomy unexpected error: %v", err)
+	}
+
+	origGroup := changelogGroupByType
+	defer func() { changelogGroupByType = origGroup }()
+	changelogGroupByType = true
+
+	feat := ChangelogEntry{Type: "feat", Description: "add login", Hash: "aaaaaaaaaaaa"}
+	fix := ChangelogEntry{Type: "fix", Description: "handle nil", Hash: "bbbbbbbbbbbb"}
+	changelog := &Changelog{
+		Title:   "Changelog",
+		Entries: []ChangelogEntry{feat, fix},
+		GroupedBy: map[string][]ChangelogEntry{
+			"feat": {feat},
+			"fix":  {fix},
+		},
+		Types: taxonomy.Types,
+	}
+
+	output := formatMarkdown(changelog)
+	fixIndex := strings.Index(output, "## Fixes")
+	featIndex := strings.Index(output, "## 🚀 Features")
+	if fixIndex == -1 || featIndex == -1 || fixIndex > featIndex {
+		t.Errorf("Expected configured fix section before features, got:\n%s", output)
+	}
+}
+
+func TestParseContributors(t *testing.T) {
+	output := "Alice\x1falice@example.com\x1fBob <bob@example.com>\x1dCarol <carol@example.com>\x1e\n" +
+		"Bob\x1fBOB@example.com\x1f\x1e\n" +
+		"Alice\x1falice@example.com\x1f\x1e"
+
+	contributors := parseContributors(output)
+	if len(contributors) != 3 {
+		t.Fatalf("Expected 3 contributors, got %d: %+v", len(contributors), contributors)
+	}
+
+	expected := []ChangelogContributor{
+		{Name: "Alice", Email: "alice@example.com", Commits: 2},
+		{Name: "Bob", Email: "bob@example.com", Commits: 2},
+		{Name: "Carol", Email: "carol@example.com", Commits: 1},
+	}
+	for i, want := range expected {
+		if contributors[i] != want {
+			t.Errorf("contributors[%d] = %+v, want %+v", i, contributors[i], want)
+		}
+	}
+}
+
+func TestParseCoAuthor(t *testing.T) {
+	tests := []struct {
+		trailer   string
+		wantName  string
+		wantEmail string
+	}{
+		{"Jane Doe <jane@example.com>", "Jane Doe", "jane@example.com"},
+		{"  Jane <jane@example.com>  ", "Jane", "jane@example.com"},
+		{"Jane Doe", "Jane Doe", ""},
+	}
+
+	for _, tt := range tests {
+		name, email := parseCoAuthor(tt.trailer)
+		if name != tt.wantName || email != tt.wantEmail {
+			t.Errorf("parseCoAuthor(%q) = %q, %q, want %q, %q", tt.trailer, name, email, tt.wantName, tt.wantEmail)
+		}
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	output := "commit aaaa\n3\t1\tcmd/root.go\n-\t-\tlogo.png\n\ncommit bbbb\n2\t0\tcmd/root.go\n10\t4\tREADME.md"
+
+	stats := parseNumstat(output)
+	want := ChangelogStats{Commits: 2, FilesChanged: 3, Insertions: 15, Deletions: 5}
+	if *stats != want {
+		t.Errorf("parseNumstat() = %+v, want %+v", *stats, want)
+	}
+}
+
+func TestFormatChangelogContributorsAndStats(t *testing.T) {
+	changelog := &Changelog{
+		Title: "Changelog",
+		Entries: []ChangelogEntry{
+			{Type: "fix", Description: "handle \"quoted\" input", Hash: "0123456789abcdef"},
+		},
+		Contributors: []ChangelogContributor{{Name: "Alice", Email: "alice@example.com", Commits: 1}},
+		Stats:        &ChangelogStats{Commits: 1, FilesChanged: 2, Insertions: 3, Deletions: 4},
+	}
+
+	markdown := formatMarkdown(changelog)
+	for _, want := range []string{"## 👥 Contributors", "Alice <alice@example.com> (1 commit)", "## 📊 Statistics", "- Deletions: 4"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown output missing %q:\n%s", want, markdown)
+		}
+	}
+
+	text := formatText(changelog)
+	for _, want := range []string{"Contributors:", "Files changed: 2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text output missing %q:\n%s", want, text)
+		}
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(formatJSON(changelog)), &document); err != nil {
+		t.Fatalf("formatJSON produced invalid JSON: %v", err)
+	}
+	if _, ok := document["contributors"]; !ok {
+		t.Error("JSON output should include contributors")
+	}
+	stats, ok := document["stats"].(map[string]interface{})
+	if !ok || stats["insertions"] != float64(3) {
+		t.Errorf("JSON output has unexpected stats: %v", document["stats"])
+	}
+
+	changelog.Contributors = nil
+	changelog.Stats = nil
+	if strings.Contains(formatJSON(changelog), "contributors") {
+		t.Error("JSON output should omit contributors when not requested")
+	}
+}
@@ -0,0 +1,471 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"esh-cli/pkg/config"
+	"esh-cli/pkg/utils"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show, change and validate the configuration file",
+	Long: `Show, change and validate the esh-cli configuration file.
+
+Keys are dotted paths into the file. A list item is selected by its index or,
+for projects, by its name, so projects.api.remote is the remote of the api
+project. Values given to set are parsed as YAML, so true, 3 and [a, b] are a
+bool, a number and a list.
+
+Settings are read from the user config ($HOME/.esh-cli.yaml or --config) and
+from a repository config, the .esh-cli.yaml closest to the working directory
+or to the --service path. Repository settings win over user settings,
+environment variables (REMOTE, PUSH_REMOTES, ...) win over both, and command
+flags win over everything. Use --repo to work on the repository config.
+
+The file carries a config_version. Files written by an older esh-cli are
+upgraded with 'esh-cli config migrate'.`,
+	Example: `  esh-cli config path
+  esh-cli config show --origin
+  esh-cli config set --repo changelog.exclude "[chore, ci]"
+  esh-cli config get remote
+  esh-cli config get projects.api
+  esh-cli config set projects.api.push_remotes "[origin, mirror]"
+  esh-cli config validate
+  esh-cli config edit
+  esh-cli config migrate`,
+}
+
+var (
+	configRepo       bool
+	configShowOrigin bool
+)
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the effective configuration, the user and repository configs merged
+with environment variables, one setting per line.
+
+With --origin each setting names where it came from: an environment
+variable, the repository config or the user config.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigShow,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the configuration file",
+	Args:  cobra.NoArgs,
+	Run:   runConfigPath,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a configuration value and write the file.
+
+The file is validated with the new value first and left unchanged when it
+would become invalid. Comments in the file are not preserved.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigSet,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file against the schema",
+	Args:  cobra.NoArgs,
+	Run:   runConfigValidate,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the configuration file and validate the result",
+	Long: `Open a copy of the configuration file in $VISUAL or $EDITOR (vi by default).
+
+The edited copy replaces the file only when it is valid; otherwise the errors
+are shown and it can be edited again or discarded.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigEdit,
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration file to the current config_version",
+	Long: `Upgrade a configuration file written by an older esh-cli to the current
+config_version. The original file is kept next to it with a .bak suffix.`,
+	Args: cobra.NoArgs,
+	Run:  runConfigMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configMigrateCmd)
+
+	configCmd.PersistentFlags().BoolVar(&configRepo, "repo", false, "Use the repository config instead of the user config")
+	configShowCmd.Flags().BoolVar(&configShowOrigin, "origin", false, "Show where each setting came from")
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) {
+	settings := config.Flatten(viper.AllSettings())
+	if len(settings) == 0 {
+		fmt.Println("No configuration found")
+		return
+	}
+
+	keys := make([]string, 0, len(settings))
+	lines := map[string]string{}
+	width := 0
+	for key, value := range settings {
+		keys = append(keys, key)
+		lines[key] = fmt.Sprintf("%s: %s", key, formatInlineValue(value))
+		width = max(width, len(lines[key]))
+	}
+	sort.Strings(keys)
+
+	sources := readConfigSources()
+	for _, key := range keys {
+		if configShowOrigin {
+			fmt.Printf("%-*s  # %s\n", width, lines[key], configOrigin(key, sources))
+		} else {
+			fmt.Println(lines[key])
+		}
+	}
+}
+
+func runConfigPath(cmd *cobra.Command, args []string) {
+	fmt.Println(configFilePath())
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) {
+	raw, err := config.ReadFile(configFilePath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	value, found := config.GetValue(raw, args[0])
+	if !found {
+		fmt.Fprintf(os.Stderr, "Error: %s is not set\n", args[0])
+		os.Exit(1)
+	}
+
+	output, err := formatConfigValue(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(output)
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) {
+	updateConfigFile(configFilePath(), func(raw map[string]interface{}) error {
+		return config.SetValue(raw, args[0], config.ParseValue(args[1]))
+	})
+	fmt.Printf("✅ Set %s\n", args[0])
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) {
+	path := configFilePath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if raw, err := config.Parse(data, path); err == nil {
+		if fileVersion, err := config.FileVersion(raw); err == nil && fileVersion < config.CurrentVersion {
+			fmt.Printf("⚠️  config_version %d is out of date, run 'esh-cli config migrate' to upgrade to %d\n",
+				fileVersion, config.CurrentVersion)
+		}
+	}
+
+	if _, errs := config.LoadData(data, path); len(errs) > 0 {
+		fmt.Printf("❌ %s is invalid:\n", path)
+		printConfigErrors(errs)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %s is valid\n", path)
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) {
+	path := configFilePath()
+	original, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	temp, err := os.CreateTemp("", "esh-cli-*.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating temporary file: %v\n", err)
+		os.Exit(1)
+	}
+	temp.Close()
+	defer os.Remove(temp.Name())
+
+	if err := os.WriteFile(temp.Name(), original, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for {
+		if err := runEditor(temp.Name()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running editor: %v\n", err)
+			os.Exit(1)
+		}
+
+		edited, err := os.ReadFile(temp.Name())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes")
+			return
+		}
+
+		_, errs := config.LoadData(edited, filepath.Base(path))
+		if len(errs) == 0 {
+			if err := os.WriteFile(path, edited, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing config file: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ Saved %s\n", path)
+			return
+		}
+
+		fmt.Println("❌ The edited configuration is invalid:")
+		printConfigErrors(errs)
+		if utils.Ask("Edit again? (y/n)") != "y" {
+			fmt.Println("Changes discarded")
+			os.Remove(temp.Name())
+			os.Exit(1)
+		}
+	}
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) {
+	path := configFilePath()
+	raw, err := config.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	applied, err := config.Migrate(raw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(applied) == 0 {
+		fmt.Printf("✅ %s is already at config_version %d\n", path, config.CurrentVersion)
+		return
+	}
+
+	backup := path + ".bak"
+	original, err := os.ReadFile(path)
+	if err == nil {
+		err = os.WriteFile(backup, original, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error backing up config file: %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.WriteFile(path, raw); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Migrated %s to config_version %d:\n", path, config.CurrentVersion)
+	for _, step := range applied {
+		fmt.Printf("  • %s\n", step)
+	}
+	fmt.Printf("The original file was saved as %s\n", backup)
+
+	if _, errs := config.Decode(raw); len(errs) > 0 {
+		fmt.Println("\n⚠️  The migrated configuration still has problems, run 'esh-cli config validate'")
+	}
+}
+
+// configFilePath returns the file the config commands work on: the user
+// config or, with --repo, the repository config of the working directory. A
+// repository without one gets it in its top-level directory.
+func configFilePath() string {
+	if !configRepo {
+		return getConfigFilePath()
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if path := findRepoConfig(dir); path != "" {
+		return path
+	}
+	root, err := utils.CmdInDir("git rev-parse --show-toplevel", dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no %s found and %s is not in a git repository\n", config.FileName, dir)
+		os.Exit(1)
+	}
+	return filepath.Join(root, config.FileName)
+}
+
+// configSourceFile is a config source with its settings
+type configSourceFile struct {
+	configSource
+	raw map[string]interface{}
+}
+
+// readConfigSources reads the config files the configuration came from,
+// highest precedence first
+func readConfigSources() []configSourceFile {
+	var files []configSourceFile
+	for i := len(configSources) - 1; i >= 0; i-- {
+		raw, err := config.ReadFile(configSources[i].Path)
+		if err != nil {
+			continue
+		}
+		files = append(files, configSourceFile{configSources[i], raw})
+	}
+	return files
+}
+
+// configOrigin names where a setting came from, following the precedence of
+// environment variables over repository over user config. Only the variable
+// viper's AutomaticEnv reads for the key counts: its upper-cased name, with
+// no prefix and no key replacer, so nested keys are not set from a variable
+// named after their section.
+func configOrigin(key string, sources []configSourceFile) string {
+	if _, ok := os.LookupEnv(strings.ToUpper(key)); ok {
+		return "env " + strings.ToUpper(key)
+	}
+	for _, source := range sources {
+		if _, ok := config.GetValue(source.raw, key); ok {
+			return fmt.Sprintf("%s %s", source.Kind, source.Path)
+		}
+	}
+	return "set at runtime"
+}
+
+// updateConfigFile applies a change to a config file, migrated to the current
+// config_version first, and writes it. Other keys are kept. The file is left
+// unchanged and the command exits when the change fails or makes it invalid.
+func updateConfigFile(path string, change func(raw map[string]interface{}) error) {
+	raw, err := config.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		raw = map[string]interface{}{"config_version": config.CurrentVersion}
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	applied, err := config.Migrate(raw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := change(raw); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if errs := validateRawConfig(raw); len(errs) > 0 {
+		fmt.Printf("❌ %s was not changed, the change makes it invalid:\n", path)
+		printConfigErrors(errs)
+		os.Exit(1)
+	}
+
+	if err := config.WriteFile(path, raw); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(applied) > 0 {
+		fmt.Printf("Migrated %s to config_version %d\n", path, config.CurrentVersion)
+	}
+}
+
+// validateRawConfig decodes and validates a raw config that is already migrated
+func validateRawConfig(raw map[string]interface{}) []error {
+	cfg, errs := config.Decode(raw)
+	if len(errs) > 0 {
+		return errs
+	}
+	return cfg.Validate()
+}
+
+// printConfigErrors lists configuration problems, one per line
+func printConfigErrors(errs []error) {
+	for _, err := range errs {
+		fmt.Printf("  ❌ %v\n", err)
+	}
+}
+
+// formatConfigValue prints scalars as they are and maps and lists as YAML
+func formatConfigValue(value interface{}) (string, error) {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes.TrimRight(data, "\n")), nil
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// formatInlineValue prints a setting on one line, lists in YAML flow style
+func formatInlineValue(value interface{}) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", value)
+	}
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		items = append(items, formatInlineValue(item))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// runEditor opens a file in the user's editor
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor may carry arguments, such as "code --wait"
+	command := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return command.Run()
+}
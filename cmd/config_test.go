@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestConfigSetGetAndMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".esh-cli.yaml")
+	legacy := "projects:\n  - name: api\n    path: /src/api\n    type: go\nproject_paths: [/src/api]\nsearch_patterns: [pocketful]\n"
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	original := cfgFile
+	cfgFile = path
+	defer func() { cfgFile = original }()
+
+	output := captureStdout(t, func() { runConfigMigrate(configMigrateCmd, nil) })
+	if !strings.Contains(output, "1 → 2") {
+		t.Errorf("migrate output should list the applied step, got:\n%s", output)
+	}
+	if backup, err := os.ReadFile(path + ".bak"); err != nil || string(backup) != legacy {
+		t.Errorf("migrate should back up the original file, got %q, %v", backup, err)
+	}
+
+	captureStdout(t, func() { runConfigSet(configSetCmd, []string{"projects.api.remote", "upstream"}) })
+
+	output = captureStdout(t, func() { runConfigGet(configGetCmd, []string{"projects.api.remote"}) })
+	if output != "upstream\n" {
+		t.Errorf("get projects.api.remote = %q, want upstream", output)
+	}
+	output = captureStdout(t, func() { runConfigGet(configGetCmd, []string{"config_version"}) })
+	if output != "2\n" {
+		t.Errorf("get config_version = %q, want 2", output)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "project_paths") {
+		t.Errorf("migrated file should drop project_paths:\n%s", data)
+	}
+}
+
+func TestValidateRawConfig(t *testing.T) {
+	errs := validateRawConfig(map[string]interface{}{
+		"config_version": 2,
+		"remote":         "origin",
+		"lint":           map[string]interface{}{"max_header_length": -1},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "max_header_length") {
+		t.Errorf("validateRawConfig() = %v, want the negative max_header_length", errs)
+	}
+
+	if errs := validateRawConfig(map[string]interface{}{"remotes": "origin"}); len(errs) != 1 {
+		t.Errorf("validateRawConfig() = %v, want the unknown key", errs)
+	}
+}
+
+func TestFormatConfigValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"origin", "origin"},
+		{true, "true"},
+		{[]interface{}{"origin", "mirror"}, "- origin\n- mirror"},
+		{map[string]interface{}{"name": "api"}, "name: api"},
+	}
+	for _, tt := range tests {
+		got, err := formatConfigValue(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("formatConfigValue(%v) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestRepoConfigPrecedence(t *testing.T) {
+	defer viper.Reset()
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	repo := filepath.Join(root, "repo")
+	service := filepath.Join(root, "service")
+	for _, dir := range []string{home, filepath.Join(repo, "sub"), service} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(home, ".esh-cli.yaml"):    "remote: origin\nlint:\n  max_header_length: 80\nprojects:\n  - name: api\n    path: " + service + "\n",
+		filepath.Join(repo, ".esh-cli.yaml"):    "remote: upstream\nchangelog:\n  exclude: [chore]\n",
+		filepath.Join(service, ".esh-cli.yaml"): "changelog:\n  exclude: [ci]\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("HOME", home)
+	t.Chdir(filepath.Join(repo, "sub"))
+	original := cfgFile
+	cfgFile = ""
+	defer func() { cfgFile = original }()
+
+	viper.Reset()
+	initConfig()
+	if got := viper.GetString("remote"); got != "upstream" {
+		t.Errorf("remote = %q, the repository config should win over the user config", got)
+	}
+	if got := viper.GetInt("lint.max_header_length"); got != 80 {
+		t.Errorf("lint.max_header_length = %d, user settings the repository does not set should stay", got)
+	}
+
+	sources := readConfigSources()
+	if got := configOrigin("remote", sources); got != "repo "+filepath.Join(repo, ".esh-cli.yaml") {
+		t.Errorf("origin of remote = %q", got)
+	}
+	if got := configOrigin("projects.api.path", sources); got != "user "+filepath.Join(home, ".esh-cli.yaml") {
+		t.Errorf("origin of projects.api.path = %q", got)
+	}
+
+	t.Setenv("REMOTE", "from-env")
+	if got := viper.GetString("remote"); got != "from-env" {
+		t.Errorf("remote = %q, environment variables should win over config files", got)
+	}
+	if got := configOrigin("remote", sources); got != "env REMOTE" {
+		t.Errorf("origin of remote = %q, want env REMOTE", got)
+	}
+
+	// Nested keys are read from the upper-cased key only, with no key replacer
+	t.Setenv("LINT_MAX_HEADER_LENGTH", "90")
+	if got := viper.GetInt("lint.max_header_length"); got != 80 {
+		t.Errorf("lint.max_header_length = %d, LINT_MAX_HEADER_LENGTH should not override it", got)
+	}
+	if got := configOrigin("lint.max_header_length", sources); got != "user "+filepath.Join(home, ".esh-cli.yaml") {
+		t.Errorf("origin of lint.max_header_length = %q, want the user config", got)
+	}
+	t.Setenv("LINT.MAX_HEADER_LENGTH", "100")
+	if got := viper.GetInt("lint.max_header_length"); got != 100 {
+		t.Errorf("lint.max_header_length = %d, LINT.MAX_HEADER_LENGTH should override it", got)
+	}
+	if got := configOrigin("lint.max_header_length", sources); got != "env LINT.MAX_HEADER_LENGTH" {
+		t.Errorf("origin of lint.max_header_length = %q, want env LINT.MAX_HEADER_LENGTH", got)
+	}
+
+	if _, err := resolveServiceScope("api"); err != nil {
+		t.Fatal(err)
+	}
+	if got := viper.GetStringSlice("changelog.exclude"); len(got) != 1 || got[0] != "ci" {
+		t.Errorf("changelog.exclude = %v, the service repository config should be merged", got)
+	}
+}
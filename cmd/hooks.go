@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"bufio"
+	"esh-cli/pkg/utils"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// managedHookMarker identifies hook scripts written by esh-cli
+const managedHookMarker = "# esh-cli managed hook"
+
+// hookBackupSuffix is appended to a hook that existed before esh-cli installed its own
+const hookBackupSuffix = ".pre-esh-cli"
+
+// managedHooks are the hooks esh-cli can install, in installation order
+var managedHooks = []string{"commit-msg", "pre-push", "post-checkout"}
+
+var (
+	hooksService     string
+	hooksAllServices bool
+	hooksNames       []string
+)
+
+// hookTarget is a repository whose hooks directory esh-cli manages
+type hookTarget struct {
+	Name     string
+	HooksDir string
+}
+
+// hooksCmd represents the hooks command
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Install esh-cli checks as git hooks",
+	Long: `Install, remove and inspect the git hooks that wire esh-cli checks into
+repositories.
+
+Managed hooks:
+- commit-msg    → lint-commits on the message being written
+- pre-push      → warns when pushing env tags that do not follow the tag format
+- post-checkout → branch-version --suggest after switching branches
+
+A hook that already exists is kept as <hook>.pre-esh-cli and runs before the
+esh-cli check. Uninstalling restores it. Hooks are installed in the current
+repository, in a configured service's repository with --service, or in every
+configured repository with --all-services.`,
+	Example: `  esh-cli hooks install                         # Install in the current repository
+  esh-cli hooks install --service api           # Install in the api project's repository
+  esh-cli hooks install --all-services          # Install in every configured repository
+  esh-cli hooks install --hook commit-msg       # Install a single hook
+  esh-cli hooks status --all-services           # Show which hooks are managed
+  esh-cli hooks uninstall                       # Remove hooks and restore previous ones`,
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install managed git hooks",
+	Args:  cobra.NoArgs,
+	Run:   runHooksInstall,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove managed git hooks and restore previous ones",
+	Args:  cobra.NoArgs,
+	Run:   runHooksUninstall,
+}
+
+var hooksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the state of the git hooks",
+	Args:  cobra.NoArgs,
+	Run:   runHooksStatus,
+}
+
+// hooksRunCmd is what the installed hook scripts call
+var hooksRunCmd = &cobra.Command{
+	Use:    "run <hook> [args...]",
+	Short:  "Run the esh-cli check of a git hook",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	Run:    runHooksRun,
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	hooksCmd.AddCommand(hooksStatusCmd)
+	hooksCmd.AddCommand(hooksRunCmd)
+
+	for _, cmd := range []*cobra.Command{hooksInstallCmd, hooksUninstallCmd, hooksStatusCmd} {
+		cmd.Flags().StringVarP(&hooksService, "service", "s", "", "Manage hooks in the service's repository")
+		cmd.Flags().BoolVar(&hooksAllServices, "all-services", false, "Manage hooks in every configured repository")
+		cmd.Flags().StringSliceVar(&hooksNames, "hook", managedHooks, "Hooks to manage (commit-msg, pre-push, post-checkout)")
+	}
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) {
+	targets := resolveHookTargets()
+
+	binary, err := os.Executable()
+	if err != nil {
+		binary = "esh-cli"
+	}
+
+	failed := false
+	for _, target := range targets {
+		fmt.Printf("🪝 %s (%s)\n", target.Name, target.HooksDir)
+		for _, hook := range hooksNames {
+			result, err := installHook(target.HooksDir, hook, binary)
+			if err != nil {
+				fmt.Printf("  ❌ %s: %v\n", hook, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("  ✅ %s: %s\n", hook, result)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) {
+	targets := resolveHookTargets()
+
+	failed := false
+	for _, target := range targets {
+		fmt.Printf("🪝 %s (%s)\n", target.Name, target.HooksDir)
+		for _, hook := range hooksNames {
+			result, err := uninstallHook(target.HooksDir, hook)
+			if err != nil {
+				fmt.Printf("  ❌ %s: %v\n", hook, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("  • %s: %s\n", hook, result)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runHooksStatus(cmd *cobra.Command, args []string) {
+	for _, target := range resolveHookTargets() {
+		fmt.Printf("🪝 %s (%s)\n", target.Name, target.HooksDir)
+		for _, hook := range hooksNames {
+			fmt.Printf("  %-14s %s\n", hook+":", hookStatus(target.HooksDir, hook))
+		}
+	}
+}
+
+func runHooksRun(cmd *cobra.Command, args []string) {
+	hook, hookArgs := args[0], args[1:]
+
+	switch hook {
+	case "commit-msg":
+		if len(hookArgs) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: commit-msg hook requires the message file\n")
+			os.Exit(lintExitError)
+		}
+		lintMessageFile = hookArgs[0]
+		runLintCommits(lintCommitsCmd, nil)
+	case "pre-push":
+		for _, warning := range checkPushedTags(os.Stdin) {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+		}
+	case "post-checkout":
+		// Only branch checkouts (flag 1) change the suggestion, not file checkouts
+		if len(hookArgs) == 3 && hookArgs[2] == "1" {
+			branchSuggest = true
+			runBranchVersion(branchVersionCmd, nil)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported hook '%s'. Use: %s\n", hook, strings.Join(managedHooks, ", "))
+		os.Exit(1)
+	}
+}
+
+// resolveHookTargets returns the repositories selected by --service and --all-services
+func resolveHookTargets() []hookTarget {
+	for _, hook := range hooksNames {
+		if !utils.ContainsString(managedHooks, hook) {
+			fmt.Fprintf(os.Stderr, "Error: unsupported hook '%s'. Use: %s\n", hook, strings.Join(managedHooks, ", "))
+			os.Exit(1)
+		}
+	}
+
+	if hooksService != "" && hooksAllServices {
+		fmt.Fprintf(os.Stderr, "Error: --service and --all-services cannot be combined\n")
+		os.Exit(1)
+	}
+
+	var scopes []serviceScope
+	switch {
+	case hooksAllServices:
+		initConfig()
+		for _, name := range configuredServiceNames() {
+			scope, err := resolveServiceScope(name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			scopes = append(scopes, scope)
+		}
+		if len(scopes) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no projects found in configuration\n")
+			os.Exit(1)
+		}
+	default:
+		scope, err := resolveServiceScope(hooksService)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		scopes = []serviceScope{scope}
+	}
+
+	// Services of a monorepo share one hooks directory
+	var targets []hookTarget
+	seen := make(map[string]bool)
+	for _, scope := range scopes {
+		hooksDir, err := findHooksDir(scope.Dir)
+		if err != nil {
+			if len(scopes) == 1 {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", scope.Name, err)
+			continue
+		}
+		if seen[hooksDir] {
+			continue
+		}
+		seen[hooksDir] = true
+
+		name := scope.Name
+		if name == "" {
+			name = "current repository"
+		}
+		targets = append(targets, hookTarget{Name: name, HooksDir: hooksDir})
+	}
+
+	return targets
+}
+
+// findHooksDir returns the hooks directory of the repository in dir,
+// honouring core.hooksPath
+func findHooksDir(dir string) (string, error) {
+	output, err := serviceScope{Dir: dir}.git("git rev-parse --git-path hooks")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %s", dir)
+	}
+
+	hooksDir := strings.TrimSpace(output)
+	if !filepath.IsAbs(hooksDir) {
+		base := dir
+		if base == "" {
+			if base, err = os.Getwd(); err != nil {
+				return "", err
+			}
+		}
+		hooksDir = filepath.Join(base, hooksDir)
+	}
+
+	return hooksDir, nil
+}
+
+// hookScript returns the managed script of a hook. An existing hook saved
+// with hookBackupSuffix runs first; pre-push input is replayed to both.
+func hookScript(hook, binary string) string {
+	var sb strings.Builder
+
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(managedHookMarker + ": remove with 'esh-cli hooks uninstall'\n")
+	sb.WriteString("hook_dir=$(dirname \"$0\")\n")
+	sb.WriteString(fmt.Sprintf("esh_cli=${ESH_CLI:-%s}\n", shellQuote(binary)))
+	sb.WriteString("command -v \"$esh_cli\" >/dev/null 2>&1 || esh_cli=esh-cli\n")
+
+	backup := fmt.Sprintf("\"$hook_dir/%s%s\"", hook, hookBackupSuffix)
+	if hook == "pre-push" {
+		sb.WriteString("input=$(cat)\n")
+		sb.WriteString(fmt.Sprintf("if [ -x %s ]; then\n", backup))
+		sb.WriteString(fmt.Sprintf("\tprintf '%%s\\n' \"$input\" | %s \"$@\" || exit $?\n", backup))
+		sb.WriteString("fi\n")
+		sb.WriteString("command -v \"$esh_cli\" >/dev/null 2>&1 || exit 0\n")
+		sb.WriteString(fmt.Sprintf("printf '%%s\\n' \"$input\" | \"$esh_cli\" hooks run %s \"$@\"\n", hook))
+	} else {
+		sb.WriteString(fmt.Sprintf("if [ -x %s ]; then\n", backup))
+		sb.WriteString(fmt.Sprintf("\t%s \"$@\" || exit $?\n", backup))
+		sb.WriteString("fi\n")
+		sb.WriteString("command -v \"$esh_cli\" >/dev/null 2>&1 || exit 0\n")
+		sb.WriteString(fmt.Sprintf("exec \"$esh_cli\" hooks run %s \"$@\"\n", hook))
+	}
+
+	return sb.String()
+}
+
+// shellQuote quotes a string for use in a POSIX shell script
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// isManagedHook reports whether the hook file at path was written by esh-cli
+func isManagedHook(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), managedHookMarker)
+}
+
+// installHook writes the managed hook, keeping an existing hook as a backup
+// that the managed hook chains to
+func installHook(hooksDir, hook, binary string) (string, error) {
+	path := filepath.Join(hooksDir, hook)
+	backupPath := path + hookBackupSuffix
+	result := "installed"
+
+	if _, err := os.Stat(path); err == nil {
+		if isManagedHook(path) {
+			result = "updated"
+		} else {
+			if _, err := os.Stat(backupPath); err == nil {
+				return "", fmt.Errorf("existing hook and %s both present, resolve manually", filepath.Base(backupPath))
+			}
+			if err := os.Rename(path, backupPath); err != nil {
+				return "", fmt.Errorf("failed to back up existing hook: %v", err)
+			}
+			result = fmt.Sprintf("installed, existing hook kept as %s", filepath.Base(backupPath))
+		}
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(hookScript(hook, binary)), 0755); err != nil {
+		return "", fmt.Errorf("failed to write hook: %v", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to make hook executable: %v", err)
+	}
+
+	return result, nil
+}
+
+// uninstallHook removes a managed hook and restores the hook it replaced.
+// Hooks not written by esh-cli are left in place.
+func uninstallHook(hooksDir, hook string) (string, error) {
+	path := filepath.Join(hooksDir, hook)
+	backupPath := path + hookBackupSuffix
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "not installed", nil
+	}
+	if !isManagedHook(path) {
+		return "not managed by esh-cli, left in place", nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove hook: %v", err)
+	}
+
+	if _, err := os.Stat(backupPath); err == nil {
+		if err := os.Rename(backupPath, path); err != nil {
+			return "", fmt.Errorf("failed to restore previous hook: %v", err)
+		}
+		return "removed, previous hook restored", nil
+	}
+
+	return "removed", nil
+}
+
+// hookStatus describes the state of a hook in a hooks directory
+func hookStatus(hooksDir, hook string) string {
+	path := filepath.Join(hooksDir, hook)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "not installed"
+	}
+	if !isManagedHook(path) {
+		return "unmanaged hook present"
+	}
+	if _, err := os.Stat(path + hookBackupSuffix); err == nil {
+		return "managed, chains previous hook"
+	}
+	return "managed"
+}
+
+// checkPushedTags reads pre-push input ("<local ref> <local sha> <remote ref>
+// <remote sha>" lines) and warns about pushed env tags that IsTagValid rejects
+func checkPushedTags(input io.Reader) []string {
+	var warnings []string
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || !strings.HasPrefix(fields[0], "refs/tags/") {
+			continue
+		}
+		// Deleted tags are pushed with an all-zero local object id
+		if strings.Trim(fields[1], "0") == "" {
+			continue
+		}
+
+		tag := strings.TrimPrefix(fields[0], "refs/tags/")
+		if looksLikeEnvTag(tag) && !utils.IsTagValid(tag) {
+			warnings = append(warnings, fmt.Sprintf(
+				"tag '%s' does not match [service_]env_X.Y.Z[-N[.M]] and will be ignored by esh-cli", tag))
+		}
+	}
+
+	return warnings
+}
+
+// looksLikeEnvTag reports whether a tag names one of the environments
+func looksLikeEnvTag(tag string) bool {
+	for _, part := range strings.Split(tag, "_") {
+		if utils.ContainsString(utils.ENVS, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHooksCmdCreation(t *testing.T) {
+	if hooksCmd == nil {
+		t.Fatal("hooksCmd should not be nil")
+	}
+
+	subcommands := make(map[string]bool)
+	for _, sub := range hooksCmd.Commands() {
+		subcommands[sub.Name()] = true
+	}
+	for _, name := range []string{"install", "uninstall", "status", "run"} {
+		if !subcommands[name] {
+			t.Errorf("Expected hooks subcommand '%s'", name)
+		}
+	}
+
+	if !hooksRunCmd.Hidden {
+		t.Error("hooks run should be hidden")
+	}
+
+	for _, flagName := range []string{"service", "all-services", "hook"} {
+		if hooksInstallCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Flag '%s' should be defined", flagName)
+		}
+	}
+}
+
+func TestInstallAndUninstallHook(t *testing.T) {
+	hooksDir := t.TempDir()
+	hookPath := filepath.Join(hooksDir, "commit-msg")
+
+	if got := hookStatus(hooksDir, "commit-msg"); got != "not installed" {
+		t.Errorf("hookStatus() = %q, want not installed", got)
+	}
+
+	// Fresh install
+	if _, err := installHook(hooksDir, "commit-msg", "/usr/local/bin/esh-cli"); err != nil {
+		t.Fatalf("installHook unexpected error: %v", err)
+	}
+	if got := hookStatus(hooksDir, "commit-msg"); got != "managed" {
+		t.Errorf("hookStatus() = %q, want managed", got)
+	}
+	if info, _ := os.Stat(hookPath); info.Mode().Perm()&0100 == 0 {
+		t.Error("Installed hook should be executable")
+	}
+
+	// Reinstall updates in place
+	if result, _ := installHook(hooksDir, "commit-msg", "/usr/local/bin/esh-cli"); result != "updated" {
+		t.Errorf("Expected reinstall to update, got %q", result)
+	}
+
+	if result, _ := uninstallHook(hooksDir, "commit-msg"); result != "removed" {
+		t.Errorf("Expected hook to be removed, got %q", result)
+	}
+
+	// An existing hook is kept and restored
+	original := "#!/bin/sh\necho original\n"
+	if err := os.WriteFile(hookPath, []byte(original), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := installHook(hooksDir, "commit-msg", "esh-cli"); err != nil {
+		t.Fatalf("installHook unexpected error: %v", err)
+	}
+	if got := hookStatus(hooksDir, "commit-msg"); got != "managed, chains previous hook" {
+		t.Errorf("hookStatus() = %q, want chained", got)
+	}
+
+	if _, err := uninstallHook(hooksDir, "commit-msg"); err != nil {
+		t.Fatalf("uninstallHook unexpected error: %v", err)
+	}
+	if content, _ := os.ReadFile(hookPath); string(content) != original {
+		t.Errorf("Expected original hook to be restored, got %q", content)
+	}
+
+	// Unmanaged hooks are left alone
+	if result, _ := uninstallHook(hooksDir, "commit-msg"); !strings.Contains(result, "left in place") {
+		t.Errorf("Expected unmanaged hook to be left in place, got %q", result)
+	}
+
+	// A leftover backup blocks installation instead of being overwritten
+	if err := os.WriteFile(hookPath+hookBackupSuffix, []byte(original), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := installHook(hooksDir, "commit-msg", "esh-cli"); err == nil {
+		t.Error("Expected error when both hook and backup exist")
+	}
+}
+
+func TestPrePushHookScriptChaining(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	hooksDir := t.TempDir()
+	logFile := filepath.Join(hooksDir, "log")
+
+	// Previous hook and fake esh-cli both record the input they receive
+	recorder := "#!/bin/sh\necho \"$0 $* $(cat)\" >> " + logFile + "\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte(recorder), 0755); err != nil {
+		t.Fatal(err)
+	}
+	fakeCli := filepath.Join(hooksDir, "fake-esh-cli")
+	if err := os.WriteFile(fakeCli, []byte(recorder), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := installHook(hooksDir, "pre-push", fakeCli); err != nil {
+		t.Fatalf("installHook unexpected error: %v", err)
+	}
+
+	cmd := exec.Command(filepath.Join(hooksDir, "pre-push"), "origin", "git@example.com:repo.git")
+	cmd.Stdin = strings.NewReader("refs/tags/stg6_1.0.0-1 abc refs/tags/stg6_1.0.0-1 000\n")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("pre-push hook failed: %v\n%s", err, output)
+	}
+
+	log, _ := os.ReadFile(logFile)
+	lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected previous hook and esh-cli to run, got:\n%s", log)
+	}
+	if !strings.Contains(lines[0], "pre-push.pre-esh-cli origin") || !strings.Contains(lines[0], "refs/tags/stg6_1.0.0-1") {
+		t.Errorf("Previous hook did not get arguments and input: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "hooks run pre-push origin") || !strings.Contains(lines[1], "refs/tags/stg6_1.0.0-1") {
+		t.Errorf("esh-cli did not get arguments and input: %q", lines[1])
+	}
+}
+
+func TestCheckPushedTags(t *testing.T) {
+	input := strings.Join([]string{
+		"refs/heads/main 1111 refs/heads/main 0000",
+		"refs/tags/stg6_1.2.3-1 1111 refs/tags/stg6_1.2.3-1 0000",
+		"refs/tags/api_stg6_1.2.3-1 1111 refs/tags/api_stg6_1.2.3-1 0000",
+		"refs/tags/stg6_1.2 1111 refs/tags/stg6_1.2 0000",
+		"refs/tags/api_production2_v1.0.0 2222 refs/tags/api_production2_v1.0.0 0000",
+		"refs/tags/v1.0.0 3333 refs/tags/v1.0.0 0000",
+		"(delete) 0000 refs/tags/dev_bad 4444",
+		"refs/tags/dev_bad 0000000000 refs/tags/dev_bad 4444",
+	}, "\n")
+
+	var got []string
+	for _, warning := range checkPushedTags(strings.NewReader(input)) {
+		got = append(got, strings.Split(warning, "'")[1])
+	}
+
+	want := []string{"stg6_1.2", "api_production2_v1.0.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkPushedTags() warned about %v, want %v", got, want)
+	}
+}
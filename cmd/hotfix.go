@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// releaseLinePattern matches the MAJOR.MINOR of a release branch
+var releaseLinePattern = regexp.MustCompile(`^\d+\.\d+$`)
+
+// Exit codes of hotfix audit
+const (
+	auditExitUnported = 1
+	auditExitError    = 2
+)
+
+var (
+	hotfixRelease string
+	hotfixEnv     string
+	hotfixService string
+	hotfixPreview bool
+	hotfixRemote  string
+	auditMain     string
+)
+
+// cherryCommit is a commit reported by git cherry
+type cherryCommit struct {
+	Hash    string
+	Subject string
+	Ported  bool
+}
+
+// unportedHotfix is a hot fix tag with commits that never reached main
+type unportedHotfix struct {
+	Tag     string
+	Commits []cherryCommit
+}
+
+// hotfixPlan is what a hot fix backport cherry-picks, pushes and tags
+type hotfixPlan struct {
+	Branch  string
+	Remotes []string
+	Commits []string
+	BaseTag string
+	NewTag  string
+}
+
+// hotfixCmd represents the hotfix command
+var hotfixCmd = &cobra.Command{
+	Use:   "hotfix <commit>...",
+	Short: "Cherry-pick commits onto a release branch and tag the hot fix",
+	Long: `Backport commits to a release branch and tag the hot fix in one step.
+
+The commits are cherry-picked with -x onto release_X.Y in a temporary
+worktree, so the working copy stays untouched. The release branch is pushed
+to the remote (origin unless configured otherwise) and the latest X.Y.Z-N tag of the environment gets the next hot fix
+number, e.g. production2_1.2.0-1 → production2_1.2.0-1.1.
+
+With --preview, the cherry-pick is tried in the temporary worktree to check
+that it applies, and nothing is pushed or tagged.`,
+	Example: `  esh-cli hotfix abc123 --release 1.2 --env production2
+  esh-cli hotfix abc123 def456 --release 1.2 --env stg6 --preview
+  esh-cli hotfix abc123 --release 1.2 --env production2 --service api`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runHotfix,
+}
+
+// hotfixAuditCmd checks that hot fixes were forward-ported to main
+var hotfixAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List hot fix commits missing from main",
+	Long: `List the commits of hot fix tags (X.Y.Z-N.M) that are not on main.
+
+Commits are compared by patch id, so a hot fix cherry-picked to main counts as
+ported even though its hash differs. Each commit is reported under the oldest
+hot fix tag containing it, followed by the cherry-pick commands that port the
+missing commits.
+
+Without --main, main and master on the remote (origin unless configured
+otherwise), then local main and master are tried in turn.
+
+Exit codes: 0 when every hot fix is on main, 1 when commits are missing,
+2 when the audit cannot run.`,
+	Example: `  esh-cli hotfix audit                    # Check against main or master
+  esh-cli hotfix audit --main develop     # Check against another branch
+  esh-cli hotfix audit --service api`,
+	Args: cobra.NoArgs,
+	Run:  runHotfixAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(hotfixCmd)
+	hotfixCmd.AddCommand(hotfixAuditCmd)
+
+	hotfixAuditCmd.Flags().StringVar(&auditMain, "main", "", "Branch hot fixes must reach (default: main or master)")
+	hotfixAuditCmd.Flags().StringVarP(&hotfixService, "service", "s", "", "Audit the service's hot fix tags")
+	hotfixAuditCmd.Flags().StringVar(&hotfixRemote, "remote", "", "Remote whose main or master is checked (default: remote setting or origin)")
+
+	hotfixCmd.Flags().StringVarP(&hotfixRelease, "release", "r", "", "Release line to backport to (MAJOR.MINOR, e.g. 1.2)")
+	hotfixCmd.Flags().StringVarP(&hotfixEnv, "env", "e", "", "Environment to tag the hot fix for")
+	hotfixCmd.Flags().StringVarP(&hotfixService, "service", "s", "", "Service name for tagging")
+	hotfixCmd.Flags().BoolVar(&hotfixPreview, "preview", false, "Check that the commits apply without pushing or tagging")
+	hotfixCmd.Flags().StringVar(&hotfixRemote, "remote", "", "Remote to push the release branch and tag to (default: remote setting or origin)")
+}
+
+func runHotfix(cmd *cobra.Command, args []string) {
+	if hotfixRelease == "" || hotfixEnv == "" {
+		fmt.Fprintf(os.Stderr, "Error: --release and --env flags are required\n")
+		os.Exit(1)
+	}
+
+	if !releaseLinePattern.MatchString(hotfixRelease) {
+		fmt.Fprintf(os.Stderr, "Error: --release must be MAJOR.MINOR, got: %s\n", hotfixRelease)
+		os.Exit(1)
+	}
+
+	if !utils.ContainsString(utils.ENVS, hotfixEnv) {
+		fmt.Fprintf(os.Stderr, "Error: invalid environment '%s'. Valid environments: %v\n",
+			hotfixEnv, utils.ENVS)
+		os.Exit(1)
+	}
+
+	scope, err := resolveServiceScope(hotfixService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	plan, err := planHotfix(scope, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🩹 Hot Fix\n")
+	fmt.Printf("Release branch: %s\n", plan.Branch)
+	fmt.Printf("Commits:        %s\n", strings.Join(shortHashes(plan.Commits), " "))
+	fmt.Printf("Base tag:       %s\n", plan.BaseTag)
+	fmt.Printf("New tag:        %s\n", plan.NewTag)
+
+	if !hotfixPreview && utils.Ask(fmt.Sprintf("Cherry-pick onto %s, push and tag %s? (y/n)", plan.Branch, plan.NewTag)) != "y" {
+		fmt.Println("Operation cancelled")
+		os.Exit(0)
+	}
+
+	commit, err := backportHotfix(scope, &plan, hotfixPreview)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hotfixPreview {
+		fmt.Printf("\n✓ %s apply cleanly to %s\n", pluralize(len(plan.Commits), "commit"), plan.Branch)
+		fmt.Printf("To push and tag the hot fix, run the same command without --preview\n")
+		return
+	}
+
+	fmt.Printf("✅ Pushed %s (%s) and tagged %s\n", plan.Branch, shortHash(commit), plan.NewTag)
+	if _, err := scope.git(fmt.Sprintf("git rev-parse --verify --quiet refs/heads/%s", plan.Branch)); err == nil {
+		fmt.Printf("Your local %s is now behind %s, update it with: git fetch %s %s:%s\n",
+			plan.Branch, plan.Remotes[0], plan.Remotes[0], plan.Branch, plan.Branch)
+	}
+}
+
+// planHotfix fetches the release branch, resolves the commits to backport and
+// finds the tag the hot fix increments
+func planHotfix(scope serviceScope, commits []string) (hotfixPlan, error) {
+	plan := hotfixPlan{Branch: "release_" + hotfixRelease, Remotes: tagRemotes(hotfixService, hotfixRemote)}
+
+	if _, err := scope.git(fmt.Sprintf("git fetch %s %s --tags", plan.Remotes[0], plan.Branch)); err != nil {
+		return plan, fmt.Errorf("release branch %s not found on %s: %v", plan.Branch, plan.Remotes[0], err)
+	}
+
+	for _, commit := range commits {
+		hash, err := scope.git(fmt.Sprintf("git rev-parse --verify %s^{commit}", commit))
+		if err != nil {
+			return plan, fmt.Errorf("commit %s not found", commit)
+		}
+		plan.Commits = append(plan.Commits, hash)
+	}
+
+	tags, err := utils.ListEnvironmentTags(hotfixEnv, hotfixService, scope.Dir)
+	if err != nil {
+		return plan, err
+	}
+
+	plan.BaseTag = latestReleaseLineTag(tags, hotfixRelease)
+	if plan.BaseTag == "" {
+		return plan, fmt.Errorf("no %s tag on release line %s to hot fix", hotfixEnv, hotfixRelease)
+	}
+
+	plan.NewTag = utils.IncrementTag(plan.BaseTag, true)
+	if plan.NewTag == "" {
+		return plan, fmt.Errorf("failed to increment tag '%s'", plan.BaseTag)
+	}
+	if _, err := scope.git(fmt.Sprintf("git rev-parse --verify --quiet refs/tags/%s", plan.NewTag)); err == nil {
+		return plan, fmt.Errorf("tag %s already exists", plan.NewTag)
+	}
+
+	return plan, nil
+}
+
+// backportHotfix cherry-picks the commits onto the release branch in a
+// temporary worktree and, unless previewing, pushes the branch and tags the
+// result under the tag lock. A hot fix number taken meanwhile moves to the
+// next one, which is recorded in the plan. It returns the new head of the
+// release branch.
+func backportHotfix(scope serviceScope, plan *hotfixPlan, preview bool) (string, error) {
+	worktree, err := os.MkdirTemp("", "esh-cli-hotfix-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create worktree directory: %v", err)
+	}
+	defer os.RemoveAll(worktree)
+
+	if _, err := scope.git(fmt.Sprintf("git worktree add --detach %s %s/%s", worktree, plan.Remotes[0], plan.Branch)); err != nil {
+		return "", fmt.Errorf("failed to check out %s in a worktree: %v", plan.Branch, err)
+	}
+	defer scope.git(fmt.Sprintf("git worktree remove --force %s", worktree))
+
+	if _, err := utils.CmdInDir(fmt.Sprintf("git cherry-pick -x %s", strings.Join(plan.Commits, " ")), worktree); err != nil {
+		conflicts, _ := utils.CmdInDir("git diff --name-only --diff-filter=U", worktree)
+		utils.CmdInDir("git cherry-pick --abort", worktree)
+		if conflicts != "" {
+			return "", fmt.Errorf("cherry-pick onto %s conflicts in:\n  %s", plan.Branch, strings.ReplaceAll(conflicts, "\n", "\n  "))
+		}
+		return "", fmt.Errorf("cherry-pick onto %s failed: %v", plan.Branch, err)
+	}
+
+	head, err := utils.CmdInDir("git rev-parse HEAD", worktree)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the cherry-picked commit: %v", err)
+	}
+
+	if preview {
+		return head, nil
+	}
+
+	if _, err := scope.git(fmt.Sprintf("git push %s %s:refs/heads/%s", plan.Remotes[0], head, plan.Branch)); err != nil {
+		return "", fmt.Errorf("failed to push %s: %v", plan.Branch, err)
+	}
+
+	comment := fmt.Sprintf("Hot fix of %s: %s", plan.Branch, strings.Join(shortHashes(plan.Commits), " "))
+	plan.NewTag, err = lockAndPushTag(scope.git, plan.NewTag, comment, head, plan.Remotes, nextReleaseNumber(scope.git, true))
+	if err != nil {
+		return "", err
+	}
+
+	return head, nil
+}
+
+func runHotfixAudit(cmd *cobra.Command, args []string) {
+	scope, err := resolveServiceScope(hotfixService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(auditExitError)
+	}
+
+	remote := tagRemotes(hotfixService, hotfixRemote)[0]
+	mainRef := auditMain
+	if mainRef == "" {
+		mainRef = findMainBranch(scope, remote)
+		if mainRef == "" {
+			fmt.Fprintf(os.Stderr, "Error: neither main nor master found, use --main\n")
+			os.Exit(auditExitError)
+		}
+	}
+
+	unported, audited, err := auditHotfixes(scope, mainRef)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(auditExitError)
+	}
+
+	if len(unported) == 0 {
+		fmt.Printf("✓ %s fully ported to %s\n", pluralize(audited, "hot fix tag"), mainRef)
+		return
+	}
+
+	var hashes []string
+	missing := 0
+	for _, hotfix := range unported {
+		fmt.Fprintf(os.Stderr, "✗ %s\n", hotfix.Tag)
+		for _, commit := range hotfix.Commits {
+			fmt.Fprintf(os.Stderr, "    %s %s\n", shortHash(commit.Hash), commit.Subject)
+			hashes = append(hashes, commit.Hash)
+			missing++
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s from %s missing on %s\n",
+		pluralize(missing, "commit"), pluralize(len(unported), "hot fix tag"), mainRef)
+	fmt.Fprintf(os.Stderr, "\n💡 Port them with:\n")
+	fmt.Fprintf(os.Stderr, "  git checkout %s\n", localBranchName(mainRef, remote))
+	fmt.Fprintf(os.Stderr, "  git cherry-pick -x %s\n", strings.Join(shortHashes(hashes), " "))
+	os.Exit(auditExitUnported)
+}
+
+// findMainBranch returns the first of main and master on the remote, then
+// main and master locally, that exists, or an empty string
+func findMainBranch(scope serviceScope, remote string) string {
+	for _, ref := range []string{remote + "/main", remote + "/master", "main", "master"} {
+		if _, err := scope.git(fmt.Sprintf("git rev-parse --verify --quiet %s^{commit}", ref)); err == nil {
+			return ref
+		}
+	}
+	return ""
+}
+
+// localBranchName returns the branch a remote-tracking ref of the remote
+// follows; other refs, including branch names with slashes, are unchanged
+func localBranchName(ref, remote string) string {
+	return strings.TrimPrefix(ref, remote+"/")
+}
+
+// auditHotfixes returns the hot fix tags with commits missing from mainRef,
+// oldest first, and the number of hot fix tags audited
+func auditHotfixes(scope serviceScope, mainRef string) ([]unportedHotfix, int, error) {
+	var tags []string
+	for _, env := range utils.ENVS {
+		envTags, err := utils.ListEnvironmentTags(env, hotfixService, scope.Dir)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, tag := range envTags {
+			if info, err := utils.ParseTag(tag); err == nil && info.IsHotFix() {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	// Oldest first, so each commit is reported under the first hot fix containing it
+	utils.SortTagsByVersion(tags)
+	seen := map[string]bool{}
+	var unported []unportedHotfix
+	for i := len(tags) - 1; i >= 0; i-- {
+		output, err := scope.git(fmt.Sprintf("git cherry -v %s %s", mainRef, tags[i]))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to compare %s with %s: %v", tags[i], mainRef, err)
+		}
+
+		hotfix := unportedHotfix{Tag: tags[i]}
+		for _, commit := range parseCherryOutput(output) {
+			if commit.Ported || seen[commit.Hash] {
+				continue
+			}
+			seen[commit.Hash] = true
+			hotfix.Commits = append(hotfix.Commits, commit)
+		}
+		if len(hotfix.Commits) > 0 {
+			unported = append(unported, hotfix)
+		}
+	}
+
+	return unported, len(tags), nil
+}
+
+// parseCherryOutput parses 'git cherry -v' lines: '+' marks a commit missing
+// upstream and '-' one with an equivalent patch upstream
+func parseCherryOutput(output string) []cherryCommit {
+	var commits []cherryCommit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(fields) < 2 || (fields[0] != "+" && fields[0] != "-") {
+			continue
+		}
+		commit := cherryCommit{Hash: fields[1], Ported: fields[0] == "-"}
+		if len(fields) == 3 {
+			commit.Subject = fields[2]
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// latestReleaseLineTag returns the newest tag whose version is on release line
+// X.Y, from tags sorted newest first
+func latestReleaseLineTag(tags []string, line string) string {
+	for _, tag := range tags {
+		if version, err := utils.GetVersionFromTag(tag); err == nil && strings.HasPrefix(version, line+".") {
+			return tag
+		}
+	}
+	return ""
+}
+
+// shortHashes abbreviates commit hashes for display
+func shortHashes(hashes []string) []string {
+	short := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		short = append(short, shortHash(hash))
+	}
+	return short
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gitIn runs a git command in dir and returns its trimmed output
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// initHotfixRepo creates a repository with a release_1.2 branch and a fix on
+// main, pushed to a bare origin, and returns the repository and the fix
+func initHotfixRepo(t *testing.T) (string, string) {
+	t.Helper()
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	work := filepath.Join(root, "work")
+	gitIn(t, root, "init", "-q", "--bare", origin)
+	gitIn(t, root, "init", "-q", "-b", "main", work)
+	gitIn(t, work, "remote", "add", "origin", origin)
+
+	writeHotfixFile(t, work, "app.txt", "v1\n")
+	gitIn(t, work, "add", ".")
+	gitIn(t, work, "commit", "-q", "-m", "feat: first release")
+	gitIn(t, work, "tag", "-a", "production2_1.2.0-1", "-m", "release")
+	gitIn(t, work, "branch", "release_1.2")
+
+	writeHotfixFile(t, work, "fix.txt", "fixed\n")
+	gitIn(t, work, "add", ".")
+	gitIn(t, work, "commit", "-q", "-m", "fix: crash on start")
+	gitIn(t, work, "push", "-q", "origin", "main", "release_1.2", "production2_1.2.0-1")
+
+	return work, gitIn(t, work, "rev-parse", "HEAD")
+}
+
+func writeHotfixFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestBackportHotfix(t *testing.T) {
+	work, fix := initHotfixRepo(t)
+	scope := serviceScope{Dir: work}
+	gitIn(t, work, "fetch", "-q", "origin")
+
+	plan := hotfixPlan{
+		Branch:  "release_1.2",
+		Remotes: []string{"origin"},
+		Commits: []string{fix},
+		BaseTag: "production2_1.2.0-1",
+		NewTag:  "production2_1.2.0-1.1",
+	}
+
+	if _, err := backportHotfix(scope, &plan, true); err != nil {
+		t.Fatalf("backportHotfix preview unexpected error: %v", err)
+	}
+	if remote := gitIn(t, work, "ls-remote", "--tags", "origin", plan.NewTag); remote != "" {
+		t.Errorf("preview should not push a tag, got %q", remote)
+	}
+
+	head, err := backportHotfix(scope, &plan, false)
+	if err != nil {
+		t.Fatalf("backportHotfix unexpected error: %v", err)
+	}
+
+	if got := gitIn(t, work, "ls-remote", "--heads", "origin", "release_1.2"); !strings.HasPrefix(got, head) {
+		t.Errorf("release_1.2 on origin = %q, want %s", got, head)
+	}
+	if got := gitIn(t, work, "rev-list", "-n", "1", plan.NewTag); got != head {
+		t.Errorf("%s points to %s, want %s", plan.NewTag, got, head)
+	}
+	if message := gitIn(t, work, "log", "-1", "--format=%B", head); !strings.Contains(message, "cherry picked from commit "+fix) {
+		t.Errorf("cherry-pick should record the original commit, got %q", message)
+	}
+
+	if branch := gitIn(t, work, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("working copy should stay on main, got %s", branch)
+	}
+	if worktrees := gitIn(t, work, "worktree", "list"); strings.Count(worktrees, "\n") != 0 {
+		t.Errorf("temporary worktree should be removed, got:\n%s", worktrees)
+	}
+}
+
+func TestBackportHotfixConflict(t *testing.T) {
+	work, _ := initHotfixRepo(t)
+
+	writeHotfixFile(t, work, "app.txt", "v2\n")
+	gitIn(t, work, "commit", "-q", "-am", "fix: change app")
+	conflicting := gitIn(t, work, "rev-parse", "HEAD")
+
+	gitIn(t, work, "checkout", "-q", "release_1.2")
+	writeHotfixFile(t, work, "app.txt", "v1-patched\n")
+	gitIn(t, work, "commit", "-q", "-am", "fix: patch app")
+	gitIn(t, work, "push", "-q", "origin", "release_1.2")
+	gitIn(t, work, "checkout", "-q", "main")
+
+	plan := hotfixPlan{Branch: "release_1.2", Remotes: []string{"origin"}, Commits: []string{conflicting}, NewTag: "production2_1.2.0-1.1"}
+	_, err := backportHotfix(serviceScope{Dir: work}, &plan, false)
+	if err == nil || !strings.Contains(err.Error(), "app.txt") {
+		t.Fatalf("expected a conflict in app.txt, got %v", err)
+	}
+	if remote := gitIn(t, work, "ls-remote", "--tags", "origin", plan.NewTag); remote != "" {
+		t.Errorf("conflicting hot fix should not be tagged, got %q", remote)
+	}
+}
+
+func TestBackportHotfixRetriesTakenNumber(t *testing.T) {
+	work, fix := initHotfixRepo(t)
+	defer func(backoff time.Duration) { tagRetryBackoff = backoff }(tagRetryBackoff)
+	tagRetryBackoff = 0
+
+	// A teammate pushes the same hot fix number first
+	gitIn(t, work, "tag", "-a", "production2_1.2.0-1.1", "-m", "teammate", "production2_1.2.0-1")
+	gitIn(t, work, "push", "-q", "origin", "production2_1.2.0-1.1")
+	gitIn(t, work, "tag", "-d", "production2_1.2.0-1.1")
+	gitIn(t, work, "fetch", "-q", "origin", "release_1.2")
+
+	plan := hotfixPlan{
+		Branch:  "release_1.2",
+		Remotes: []string{"origin"},
+		Commits: []string{fix},
+		BaseTag: "production2_1.2.0-1",
+		NewTag:  "production2_1.2.0-1.1",
+	}
+	var head string
+	var err error
+	output := captureStdout(t, func() {
+		head, err = backportHotfix(serviceScope{Dir: work}, &plan, false)
+	})
+	if err != nil {
+		t.Fatalf("backportHotfix unexpected error: %v\n%s", err, output)
+	}
+	if plan.NewTag != "production2_1.2.0-1.2" {
+		t.Errorf("plan.NewTag = %s, want the next hot fix number production2_1.2.0-1.2", plan.NewTag)
+	}
+	if got := gitIn(t, work, "ls-remote", "--tags", "origin", "production2_1.2.0-1.2^{}"); !strings.HasPrefix(got, head) {
+		t.Errorf("production2_1.2.0-1.2 on origin = %q, want %s", got, head)
+	}
+}
+
+func TestLatestReleaseLineTag(t *testing.T) {
+	tags := []string{"production2_1.3.0-1", "production2_1.2.1-2.1", "production2_1.2.0-1", "production2_1.20.0-1"}
+
+	if got := latestReleaseLineTag(tags, "1.2"); got != "production2_1.2.1-2.1" {
+		t.Errorf("latestReleaseLineTag(1.2) = %q", got)
+	}
+	if got := latestReleaseLineTag(tags, "2.0"); got != "" {
+		t.Errorf("latestReleaseLineTag(2.0) = %q, want none", got)
+	}
+}
+
+func TestAuditHotfixes(t *testing.T) {
+	work, fix := initHotfixRepo(t)
+	scope := serviceScope{Dir: work}
+
+	// A hot fix cherry-picked from main is ported by patch id
+	gitIn(t, work, "checkout", "-q", "release_1.2")
+	gitIn(t, work, "cherry-pick", "-x", fix)
+	gitIn(t, work, "tag", "-a", "production2_1.2.0-1.1", "-m", "hot fix")
+
+	// A hot fix made on the release branch only is not
+	writeHotfixFile(t, work, "release-only.txt", "fix\n")
+	gitIn(t, work, "add", ".")
+	gitIn(t, work, "commit", "-q", "-m", "fix: release only")
+	missing := gitIn(t, work, "rev-parse", "HEAD")
+	gitIn(t, work, "tag", "-a", "production2_1.2.0-1.2", "-m", "hot fix")
+	gitIn(t, work, "tag", "-a", "stg6_1.2.0-1.2", "-m", "hot fix")
+	gitIn(t, work, "checkout", "-q", "main")
+
+	unported, audited, err := auditHotfixes(scope, "main")
+	if err != nil {
+		t.Fatalf("auditHotfixes unexpected error: %v", err)
+	}
+	if audited != 3 {
+		t.Errorf("audited = %d, want 3", audited)
+	}
+	if len(unported) != 1 || len(unported[0].Commits) != 1 {
+		t.Fatalf("expected one unported commit, got %+v", unported)
+	}
+	if unported[0].Commits[0].Hash != missing || unported[0].Commits[0].Subject != "fix: release only" {
+		t.Errorf("unexpected unported commit %+v", unported[0].Commits[0])
+	}
+
+	gitIn(t, work, "cherry-pick", "-x", missing)
+	if unported, _, _ := auditHotfixes(scope, "main"); len(unported) != 0 {
+		t.Errorf("expected everything ported after cherry-pick, got %+v", unported)
+	}
+
+	if found := findMainBranch(scope, "origin"); found != "origin/main" {
+		t.Errorf("findMainBranch() = %q, want origin/main", found)
+	}
+}
+
+func TestLocalBranchName(t *testing.T) {
+	tests := []struct {
+		ref, remote, expected string
+	}{
+		{"origin/main", "origin", "main"},
+		{"upstream/release/1.x", "upstream", "release/1.x"},
+		{"release/1.x", "origin", "release/1.x"},
+		{"main", "origin", "main"},
+	}
+
+	for _, tt := range tests {
+		if got := localBranchName(tt.ref, tt.remote); got != tt.expected {
+			t.Errorf("localBranchName(%q, %q) = %q, want %q", tt.ref, tt.remote, got, tt.expected)
+		}
+	}
+}
+
+func TestParseCherryOutput(t *testing.T) {
+	output := "+ 1111111 fix: missing\n- 2222222 fix: ported\n\n+ 3333333\n"
+
+	commits := parseCherryOutput(output)
+	if len(commits) != 3 {
+		t.Fatalf("parseCherryOutput() returned %d commits, want 3", len(commits))
+	}
+	if commits[0].Ported || commits[0].Subject != "fix: missing" {
+		t.Errorf("unexpected first commit %+v", commits[0])
+	}
+	if !commits[1].Ported {
+		t.Errorf("'-' commit should be ported: %+v", commits[1])
+	}
+	if commits[2].Hash != "3333333" || commits[2].Subject != "" {
+		t.Errorf("unexpected third commit %+v", commits[2])
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"errors"
+	"esh-cli/pkg/config"
 	"esh-cli/pkg/utils"
 	"fmt"
 	"os"
@@ -14,18 +16,49 @@ import (
 var (
 	initSearchDepth int
 	initForce       bool
+	initMerge       bool
+	initRequireGit  bool
 	initPatterns    []string
+	initRoots       []string
 )
 
+// initIgnoreFile lists directories init does not search, in a search root or
+// in the home directory
+const initIgnoreFile = ".esh-cli-ignore"
+
+// projectIndicators are files and folders that mark a project directory
+var projectIndicators = []string{
+	".git",
+	"package.json",
+	"go.mod",
+	"requirements.txt",
+	"docker-compose.yml",
+	"pom.xml",
+	"build.gradle",
+	"Cargo.toml",
+}
+
 // initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize ESH CLI by discovering projects in your workspace",
 	Long: `Initialize ESH CLI configuration by automatically discovering projects in your workspace.
-You can specify patterns to search for, or let it discover all projects automatically.`,
+You can specify patterns to search for, or let it discover all projects automatically.
+
+A directory is a project when it has a .git folder or a build file such as
+go.mod or package.json; --require-git only accepts git repositories. The
+search does not descend into projects, and skips the directories listed in a
+.esh-cli-ignore file in a search root or in the home directory.
+
+The git remote URL of each project is saved as remote_url. With --merge, new
+projects are added to the existing configuration, and a configured project
+whose directory moved is found again by its remote URL and keeps its name and
+settings.`,
 	Example: `  esh-cli init - discover all projects automatically
   esh-cli init --patterns "myapp,service" - discover projects containing "myapp" or "service"
   esh-cli init --depth 3 - search up to 3 directories deep
+  esh-cli init --roots ~/work,~/oss --require-git - search only these directories for git repositories
+  esh-cli init --merge - add new projects and keep the configured ones
   esh-cli init --force - overwrite existing configuration`,
 	Run: runInit,
 }
@@ -35,30 +68,50 @@ func init() {
 	initCmd.Flags().IntVarP(&initSearchDepth, "depth", "d", 2, "maximum search depth for project discovery")
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "force overwrite existing configuration")
 	initCmd.Flags().StringSliceVarP(&initPatterns, "patterns", "p", []string{}, "patterns to search for in project names (comma-separated)")
+	initCmd.Flags().StringSliceVar(&initRoots, "roots", []string{}, "directories to search instead of the common workspace folders (comma-separated)")
+	initCmd.Flags().BoolVar(&initRequireGit, "require-git", false, "only discover git repositories")
+	initCmd.Flags().BoolVar(&initMerge, "merge", false, "add new projects to the existing configuration and keep the configured ones")
 }
 
 func runInit(cmd *cobra.Command, args []string) {
 	fmt.Println("🤖 ESH CLI Initialization Starting...")
 
+	if initForce && initMerge {
+		fmt.Fprintf(os.Stderr, "Error: --force and --merge cannot be used together\n")
+		os.Exit(1)
+	}
+
 	// Check if config already exists
-	if !initForce && configExists() {
-		fmt.Println("⚠️  Configuration already exists. Use --force to overwrite.")
+	if !initForce && !initMerge && configExists() {
+		fmt.Println("⚠️  Configuration already exists. Use --merge to add new projects or --force to overwrite.")
 		fmt.Printf("Current config file: %s\n", viper.ConfigFileUsed())
 		return
 	}
 
-	var projects []Project
-	var err error
+	var existing []Project
+	if initMerge {
+		var err error
+		if existing, err = configuredProjects(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	roots := getSearchPaths()
+	if len(initRoots) > 0 {
+		roots = nil
+		for _, root := range initRoots {
+			roots = append(roots, expandRoot(root))
+		}
+	}
 
 	// Use patterns from flag, or discover all projects if none specified
 	if len(initPatterns) > 0 {
 		fmt.Printf("Searching for projects containing patterns: %v\n", initPatterns)
-		projects, err = discoverSpecificProjects(initPatterns)
 	} else {
 		fmt.Println("Discovering all projects in your workspace...")
-		projects, err = discoverProjects("")
 	}
-
+	projects, err := discoverProjects(roots, initPatterns)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error discovering projects: %v\n", err)
 		os.Exit(1)
@@ -81,6 +134,24 @@ func runInit(cmd *cobra.Command, args []string) {
 		fmt.Printf("  %d. %s (%s)\n", i+1, project.Name, project.Path)
 	}
 
+	if initMerge {
+		merge := mergeProjects(existing, projects)
+		for _, project := range merge.Added {
+			fmt.Printf("  ➕ %s (%s)\n", project.Name, project.Path)
+		}
+		for _, move := range merge.Moved {
+			fmt.Printf("  🚚 %s\n", move)
+		}
+		for _, identity := range merge.Identified {
+			fmt.Printf("  🔗 %s\n", identity)
+		}
+		if len(merge.Added) == 0 && len(merge.Moved) == 0 && len(merge.Identified) == 0 {
+			fmt.Printf("\n✅ All discovered projects are already configured\n")
+			return
+		}
+		projects = merge.Projects
+	}
+
 	// Save to config
 	err = saveProjectsToConfig(projects)
 	if err != nil {
@@ -93,103 +164,289 @@ func runInit(cmd *cobra.Command, args []string) {
 	fmt.Printf("Found %d projects ready for ESH CLI management.\n", len(projects))
 }
 
-// Project represents a discovered project
-type Project struct {
-	Name string `json:"name"`
-	Path string `json:"path"`
-	Type string `json:"type"`
-}
+// Project represents a discovered project, as saved in the configuration
+type Project = config.Project
 
-// discoverProjects searches for projects on the system
-func discoverProjects(projectPattern string) ([]Project, error) {
+// discoverProjects searches the roots for project directories. With patterns,
+// only projects whose name contains one of them are kept.
+func discoverProjects(roots []string, patterns []string) ([]Project, error) {
 	var projects []Project
 
-	// Get common search paths
-	searchPaths := getSearchPaths()
-
-	// Project indicators - files/folders that indicate a project
-	projectIndicators := []string{
-		".git",
-		"package.json",
-		"go.mod",
-		"requirements.txt",
-		"Dockerfile",
-		".env",
-		"docker-compose.yml",
-		"pom.xml",
-		"build.gradle",
-		"Cargo.toml",
-	}
-
-	// Common project name patterns that might be relevant for ESH CLI
-	projectNamePatterns := []string{
-		"backoffice",
-		"backend",
-		"frontend",
-		"api",
-		"service",
-		"app",
-		"web",
-		"server",
-		"client",
-		"admin",
-		"dashboard",
-		"portal",
-		"platform",
-		"core",
-		"main",
-		"primary",
-	}
-
-	for _, searchPath := range searchPaths {
-		fmt.Printf("🔍 Searching in: %s\n", searchPath)
-
-		err := filepath.WalkDir(searchPath, func(path string, d os.DirEntry, err error) error {
+	indicators := projectIndicators
+	if initRequireGit {
+		indicators = []string{".git"}
+	}
+
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil || !info.IsDir() {
+			if len(initRoots) > 0 {
+				fmt.Printf("Warning: %s is not a directory, skipping\n", root)
+			}
+			continue
+		}
+		fmt.Printf("🔍 Searching in: %s\n", root)
+		ignore := loadIgnoreRules(root)
+
+		err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
 			if err != nil {
 				return nil // Continue on errors
 			}
+			if !d.IsDir() {
+				return nil
+			}
 
 			// Skip if we've exceeded search depth
-			if getDirectoryDepth(searchPath, path) > initSearchDepth {
-				if d.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
+			if getDirectoryDepth(root, path) > initSearchDepth {
+				return filepath.SkipDir
 			}
 
-			// Skip hidden directories and common ignore patterns
-			if d.IsDir() && shouldSkipDirectory(d.Name()) {
+			// Skip hidden directories, common ignore patterns and ignored directories
+			if path != root && (shouldSkipDirectory(d.Name()) || ignore.ignores(path)) {
 				return filepath.SkipDir
 			}
 
-			// Check if this directory contains project indicators
-			if d.IsDir() {
-				if isProjectDirectory(path, projectIndicators) {
-					projectName := filepath.Base(path)
-
-					// Check if project name matches our patterns or contains relevant keywords
-					if isRelevantProject(projectName, projectNamePatterns, projectPattern) {
-						projectType := determineProjectType(path)
-						projects = append(projects, Project{
-							Name: projectName,
-							Path: path,
-							Type: projectType,
-						})
-					}
-				}
+			if !isProjectDirectory(path, indicators) {
+				return nil
+			}
+
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				absPath = path
+			}
+			projectName := filepath.Base(absPath)
+			if len(patterns) == 0 || containsAnyPattern(projectName, patterns) {
+				projects = append(projects, Project{
+					Name:      projectName,
+					Path:      absPath,
+					Type:      determineProjectType(path),
+					RemoteURL: projectRemoteURL(absPath),
+				})
 			}
 
+			// Folders of a project are part of it, not projects of their own
+			if path != root {
+				return filepath.SkipDir
+			}
 			return nil
 		})
 
 		if err != nil {
-			fmt.Printf("Warning: Error searching %s: %v\n", searchPath, err)
+			fmt.Printf("Warning: Error searching %s: %v\n", root, err)
 		}
 	}
 
 	return removeDuplicateProjects(projects), nil
 }
 
+// ignoreRules are the .esh-cli-ignore patterns of a search root. A pattern
+// containing a slash matches a path relative to the root, other patterns
+// match directory names.
+type ignoreRules struct {
+	root     string
+	patterns []string
+}
+
+// loadIgnoreRules reads the .esh-cli-ignore files of a search root and of the
+// home directory. Blank lines and lines starting with # are skipped.
+func loadIgnoreRules(root string) ignoreRules {
+	rules := ignoreRules{root: root}
+	files := []string{filepath.Join(root, initIgnoreFile)}
+	if home, err := os.UserHomeDir(); err == nil && filepath.Clean(home) != filepath.Clean(root) {
+		files = append(files, filepath.Join(home, initIgnoreFile))
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			rules.patterns = append(rules.patterns, strings.Trim(line, "/"))
+		}
+	}
+	return rules
+}
+
+// ignores reports whether a directory below the root matches an ignore pattern
+func (r ignoreRules) ignores(path string) bool {
+	rel, err := filepath.Rel(r.root, path)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range r.patterns {
+		target := filepath.Base(path)
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if matched, _ := filepath.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// projectRemoteURL returns the URL of the origin remote of a git repository,
+// or of its first remote, so the project can be recognized after it moved
+func projectRemoteURL(path string) string {
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return ""
+	}
+	if url, err := utils.CmdInDir("git remote get-url origin", path); err == nil {
+		return url
+	}
+	remotes, err := utils.CmdInDir("git remote", path)
+	if err != nil || remotes == "" {
+		return ""
+	}
+	url, _ := utils.CmdInDir(fmt.Sprintf("git remote get-url %s", strings.Fields(remotes)[0]), path)
+	return url
+}
+
+// normalizeRemoteURL reduces the ssh, https and scp-like forms of a git
+// remote URL to host/path, so the forms of one repository compare equal
+func normalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return ""
+	}
+	if scheme := strings.Index(url, "://"); scheme >= 0 {
+		url = url[scheme+3:]
+	} else if colon := strings.Index(url, ":"); colon >= 0 && !strings.Contains(url[:colon], "/") {
+		// scp-like git@host:org/repo
+		url = url[:colon] + "/" + url[colon+1:]
+	}
+	if at := strings.Index(url, "@"); at >= 0 && at < strings.Index(url+"/", "/") {
+		url = url[at+1:]
+	}
+
+	host, path, _ := strings.Cut(url, "/")
+	if colon := strings.Index(host, ":"); colon >= 0 {
+		host = host[:colon]
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	return strings.ToLower(host) + "/" + path
+}
+
+// initMergeResult is the outcome of merging discovered projects into the configured ones
+type initMergeResult struct {
+	Projects   []Project
+	Added      []Project
+	Moved      []string
+	Identified []string
+}
+
+// mergeProjects adds discovered projects to the configured ones. A discovered
+// project at the path of a configured one is already known, and gives it its
+// remote URL when it was configured without one. One with the
+// remote URL of configured projects whose directory is gone is the same
+// project moved: they keep their names and settings and follow it. New
+// projects whose name is taken get a numbered name.
+func mergeProjects(existing, discovered []Project) initMergeResult {
+	result := initMergeResult{Projects: append([]Project{}, existing...)}
+
+	for _, project := range discovered {
+		if i := configuredIndex(result.Projects, project.Path); i >= 0 {
+			configured := &result.Projects[i]
+			if configured.RemoteURL == "" && project.RemoteURL != "" {
+				configured.RemoteURL = project.RemoteURL
+				result.Identified = append(result.Identified, fmt.Sprintf("%s is %s", configured.Name, project.RemoteURL))
+			}
+			continue
+		}
+
+		identity := normalizeRemoteURL(project.RemoteURL)
+		sameRepository := false
+		for i := range result.Projects {
+			configured := &result.Projects[i]
+			if identity == "" || normalizeRemoteURL(configured.RemoteURL) != identity {
+				continue
+			}
+			sameRepository = true
+			if _, err := os.Stat(configured.Path); err != nil {
+				result.Moved = append(result.Moved, fmt.Sprintf("%s moved from %s to %s", configured.Name, configured.Path, project.Path))
+				configured.Path = project.Path
+			}
+		}
+		// Another clone of a configured repository is not a new project
+		if sameRepository {
+			continue
+		}
+
+		project.Name = uniqueProjectName(result.Projects, project.Name)
+		result.Projects = append(result.Projects, project)
+		result.Added = append(result.Added, project)
+	}
+	return result
+}
+
+// configuredIndex returns the index of the project configured at a path, or -1
+func configuredIndex(projects []Project, path string) int {
+	for i, project := range projects {
+		if filepath.Clean(project.Path) == filepath.Clean(path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// uniqueProjectName numbers a project name taken by a configured project's
+// name or alias: api, api-2, api-3, ...
+func uniqueProjectName(projects []Project, name string) string {
+	taken := func(candidate string) bool {
+		for _, project := range projects {
+			if project.Matches(candidate) {
+				return true
+			}
+		}
+		return false
+	}
+
+	candidate := name
+	for n := 2; taken(candidate); n++ {
+		candidate = fmt.Sprintf("%s-%d", name, n)
+	}
+	return candidate
+}
+
+// configuredProjects reads the projects of the user config file
+func configuredProjects() ([]Project, error) {
+	path := getConfigFilePath()
+	raw, err := config.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.Migrate(raw); err != nil {
+		return nil, err
+	}
+	cfg, errs := config.Decode(raw)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s is invalid, run 'esh-cli config validate': %v", path, errs[0])
+	}
+	return cfg.Projects, nil
+}
+
+// expandRoot expands a leading ~ of a --roots directory, which the shell
+// leaves alone after a comma, and makes it absolute
+func expandRoot(root string) string {
+	if root == "~" || strings.HasPrefix(root, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			root = filepath.Join(home, root[1:])
+		}
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		return abs
+	}
+	return root
+}
+
 // getSearchPaths returns common paths to search for projects
 func getSearchPaths() []string {
 	home, err := os.UserHomeDir()
@@ -243,31 +500,6 @@ func isProjectDirectory(dirPath string, indicators []string) bool {
 	return false
 }
 
-// isRelevantProject checks if project name is relevant for ESH CLI
-func isRelevantProject(projectName string, patterns []string, projectPattern string) bool {
-	lowerName := strings.ToLower(projectName)
-
-	// First, check for the specific project pattern (highest priority)
-	if projectPattern != "" && strings.Contains(lowerName, strings.ToLower(projectPattern)) {
-		return true
-	}
-
-	// Check for exact matches or contains patterns
-	for _, pattern := range patterns {
-		if strings.Contains(lowerName, pattern) {
-			return true
-		}
-	}
-
-	// Additional heuristics - check for common naming conventions
-	if strings.Contains(lowerName, "service") ||
-		strings.Contains(lowerName, "microservice") {
-		return true
-	}
-
-	return false
-}
-
 // determineProjectType analyzes project directory to determine type
 func determineProjectType(projectPath string) string {
 	// Check for specific files to determine project type
@@ -340,32 +572,36 @@ func getConfigFilePath() string {
 	return filepath.Join(home, ".esh-cli.yaml")
 }
 
-// saveProjectsToConfig saves discovered projects to viper configuration
+// saveProjectsToConfig saves discovered projects to the user config file.
+// Other keys of the file are kept; settings merged from a repository config
+// are not written into it.
 func saveProjectsToConfig(projects []Project) error {
-	// Extract project paths for easy access
-	var projectPaths []string
-	for _, project := range projects {
-		projectPaths = append(projectPaths, project.Path)
+	configPath := getConfigFilePath()
+	raw, err := config.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		raw = map[string]interface{}{}
+	} else if err != nil {
+		return err
+	}
+	if _, err := config.Migrate(raw); err != nil {
+		return err
 	}
 
 	// Set up configuration structure
-	config := map[string]interface{}{
+	settings := map[string]interface{}{
+		"config_version":  config.CurrentVersion,
 		"projects":        projects,
-		"project_paths":   projectPaths,
 		"initialized_at":  fmt.Sprintf("%v", utils.GetCurrentTime()),
 		"version":         version,
 		"auto_discovered": true,
-		"search_patterns": []string{"pocketful", "bank_1"},
 	}
 
-	// Set all values in viper
-	for key, value := range config {
+	// Set all values in the file and in viper
+	for key, value := range settings {
+		raw[key] = value
 		viper.Set(key, value)
 	}
 
-	// Write config file
-	configPath := getConfigFilePath()
-
 	// Ensure directory exists
 	dir := filepath.Dir(configPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -373,81 +609,7 @@ func saveProjectsToConfig(projects []Project) error {
 	}
 
 	// Write configuration
-	if err := viper.WriteConfigAs(configPath); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
-	}
-
-	return nil
-}
-
-// discoverSpecificProjects searches for projects containing specific patterns
-func discoverSpecificProjects(targetPatterns []string) ([]Project, error) {
-	var projects []Project
-
-	// Get common search paths
-	searchPaths := getSearchPaths()
-
-	// Project indicators - files/folders that indicate a project
-	projectIndicators := []string{
-		".git",
-		"package.json",
-		"go.mod",
-		"requirements.txt",
-		"Dockerfile",
-		".env",
-		"docker-compose.yml",
-		"pom.xml",
-		"build.gradle",
-		"Cargo.toml",
-	}
-
-	for _, searchPath := range searchPaths {
-		fmt.Printf("🔍 Searching in: %s\n", searchPath)
-
-		err := filepath.WalkDir(searchPath, func(path string, d os.DirEntry, err error) error {
-			if err != nil {
-				return nil // Continue on errors
-			}
-
-			// Skip if we've exceeded search depth
-			if getDirectoryDepth(searchPath, path) > initSearchDepth {
-				if d.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-
-			// Skip hidden directories and common ignore patterns
-			if d.IsDir() && shouldSkipDirectory(d.Name()) {
-				return filepath.SkipDir
-			}
-
-			// Check if this directory contains project indicators
-			if d.IsDir() {
-				if isProjectDirectory(path, projectIndicators) {
-					projectName := filepath.Base(path)
-
-					// Check if project name contains any of our target patterns
-					if containsAnyPattern(projectName, targetPatterns) {
-						projectType := determineProjectType(path)
-						projects = append(projects, Project{
-							Name: projectName,
-							Path: path,
-							Type: projectType,
-						})
-					}
-				}
-			}
-
-			return nil
-		})
-
-		if err != nil {
-			fmt.Printf("Warning: Error searching %s: %v\n", searchPath, err)
-		}
-	}
-
-	return removeDuplicateProjects(projects), nil
+	return config.WriteFile(configPath, raw)
 }
 
 // containsAnyPattern checks if project name contains any of the target patterns
@@ -581,6 +581,9 @@ func TestRunInit(t *testing.T) {
 				initPatterns = originalPatterns
 			}()
 
+			// Keep discovery and the written config out of the real home directory
+			t.Setenv("HOME", t.TempDir())
+
 			// Set up test conditions
 			initForce = tt.force
 			initSearchDepth = 1       // Limit search depth for faster tests
@@ -705,59 +708,130 @@ func TestGetConfigFilePath(t *testing.T) {
 	}
 }
 
-func TestIsRelevantProject(t *testing.T) {
-	// Test the isRelevantProject function with various directory names
-	tests := []struct {
-		name           string
-		dirName        string
-		patterns       []string
-		projectPattern string
-		expected       bool
-	}{
-		{
-			name:           "matches service pattern",
-			dirName:        "user-service",
-			patterns:       []string{"service", "app"},
-			projectPattern: "",
-			expected:       true,
-		},
-		{
-			name:           "matches app pattern",
-			dirName:        "myapp-frontend",
-			patterns:       []string{"service", "app"},
-			projectPattern: "",
-			expected:       true,
-		},
-		{
-			name:           "no match without service keyword",
-			dirName:        "some-other-project",
-			patterns:       []string{"service", "app"},
-			projectPattern: "",
-			expected:       false,
-		},
-		{
-			name:           "matches project pattern",
-			dirName:        "my-special-app",
-			patterns:       []string{"service", "app"},
-			projectPattern: "special",
-			expected:       true,
-		},
-		{
-			name:           "matches service heuristic",
-			dirName:        "user-service",
-			patterns:       []string{},
-			projectPattern: "",
-			expected:       true, // Should match due to "service" heuristic
-		},
+func TestDiscoverProjects(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	for _, dir := range []string{"api", "web/frontend", "lone", "scratch/tool", "env-only"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitIn(t, root, "init", "-q", "api")
+	gitIn(t, filepath.Join(root, "api"), "remote", "add", "origin", "git@github.com:acme/api.git")
+	for file, content := range map[string]string{
+		"web/package.json":          "{}",
+		"web/frontend/package.json": "{}",
+		"lone/Dockerfile":           "FROM scratch",
+		"scratch/tool/go.mod":       "module tool",
+		"env-only/.env":             "A=1",
+		initIgnoreFile:              "# experiments\nscratch/\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := isRelevantProject(tt.dirName, tt.patterns, tt.projectPattern)
-			if result != tt.expected {
-				t.Errorf("isRelevantProject(%q, %v, %q) = %v, expected %v",
-					tt.dirName, tt.patterns, tt.projectPattern, result, tt.expected)
-			}
-		})
+	originalDepth, originalRequireGit := initSearchDepth, initRequireGit
+	defer func() { initSearchDepth, initRequireGit = originalDepth, originalRequireGit }()
+	initSearchDepth = 3
+
+	names := func(projects []Project) []string {
+		var names []string
+		for _, project := range projects {
+			names = append(names, project.Name)
+		}
+		return names
+	}
+
+	projects, err := discoverProjects([]string{root}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(names(projects), ","), "api,web"; got != want {
+		t.Errorf("discoverProjects() = %s, want %s", got, want)
+	}
+	if projects[0].RemoteURL != "git@github.com:acme/api.git" {
+		t.Errorf("remote_url = %q, want the origin URL", projects[0].RemoteURL)
+	}
+
+	initRequireGit = true
+	projects, _ = discoverProjects([]string{root}, nil)
+	if got := strings.Join(names(projects), ","); got != "api" {
+		t.Errorf("discoverProjects() with --require-git = %s, want api", got)
+	}
+}
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	want := "github.com/acme/api"
+	for _, url := range []string{
+		"git@github.com:acme/api.git",
+		"ssh://git@github.com:22/acme/api.git",
+		"https://github.com/acme/api",
+		"https://user@GitHub.com/acme/api.git/",
+	} {
+		if got := normalizeRemoteURL(url); got != want {
+			t.Errorf("normalizeRemoteURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+	if got := normalizeRemoteURL("/srv/git/api.git"); got != "/srv/git/api" {
+		t.Errorf("normalizeRemoteURL(local path) = %q", got)
+	}
+}
+
+func TestExpandRoot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for root, want := range map[string]string{
+		"~/oss":      filepath.Join(home, "oss"),
+		"~":          home,
+		"work":       filepath.Join(wd, "work"),
+		"/srv/code/": "/srv/code",
+		"~other/src": filepath.Join(wd, "~other/src"),
+	} {
+		if got := expandRoot(root); got != want {
+			t.Errorf("expandRoot(%q) = %q, want %q", root, got, want)
+		}
+	}
+}
+
+func TestMergeProjects(t *testing.T) {
+	kept := t.TempDir()
+	moved := t.TempDir()
+	existing := []Project{
+		{Name: "api", Path: "/gone/api", RemoteURL: "git@github.com:acme/api.git", Aliases: []string{"a"}},
+		{Name: "web", Path: kept, Type: "nodejs"},
+	}
+	discovered := []Project{
+		{Name: "api-v2", Path: moved, RemoteURL: "https://github.com/acme/api"},
+		{Name: "web", Path: kept, RemoteURL: "git@github.com:acme/web.git"},
+		{Name: "a", Path: "/src/other/a"},
+		{Name: "web", Path: "/src/other/web"},
+	}
+
+	result := mergeProjects(existing, discovered)
+
+	if result.Projects[0].Name != "api" || result.Projects[0].Path != moved || len(result.Projects[0].Aliases) != 1 {
+		t.Errorf("moved project = %+v, want api with its settings at %s", result.Projects[0], moved)
+	}
+	if len(result.Moved) != 1 {
+		t.Errorf("Moved = %v, want one move", result.Moved)
+	}
+	if result.Projects[1].Type != "nodejs" {
+		t.Errorf("configured project = %+v, should be kept as configured", result.Projects[1])
+	}
+	if result.Projects[1].RemoteURL != "git@github.com:acme/web.git" || len(result.Identified) != 1 {
+		t.Errorf("configured project without remote_url = %+v (%v), should get the discovered one", result.Projects[1], result.Identified)
+	}
+	var added []string
+	for _, project := range result.Added {
+		added = append(added, project.Name)
+	}
+	if got := strings.Join(added, ","); got != "a-2,web-2" {
+		t.Errorf("Added = %s, want new projects with free names a-2,web-2", got)
 	}
 }
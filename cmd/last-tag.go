@@ -1,10 +1,10 @@
 package cmd
 
 import (
+	"esh-cli/pkg/config"
 	"esh-cli/pkg/utils"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -12,6 +12,7 @@ import (
 
 var (
 	lastTagService string
+	lastTagRemote  bool
 )
 
 // lastTagCmd represents the last-tag command
@@ -19,10 +20,14 @@ var lastTagCmd = &cobra.Command{
 	Use:   "last-tag [environment]",
 	Short: "Shows the last tag for a given environment",
 	Long: `Shows the last tag and its comment for a given environment.
-This is useful for checking the current state before creating new tags.`,
+This is useful for checking the current state before creating new tags.
+
+With --remote, the tag is read from the remote with git ls-remote, which shows
+what was pushed without fetching. The comment is only shown for fetched tags.`,
 	Example: `  esh-cli last-tag stg6 - shows last tag for staging in current directory
   esh-cli last-tag production2 - shows last tag for production in current directory
-  esh-cli last-tag stg6 --service myservice - shows last tag for specific service`,
+  esh-cli last-tag stg6 --service myservice - shows last tag for specific service
+  esh-cli last-tag production2 --remote - shows last tag pushed to the remote`,
 	Args: cobra.ExactArgs(1),
 	Run:  runLastTag,
 }
@@ -30,6 +35,7 @@ This is useful for checking the current state before creating new tags.`,
 func init() {
 	rootCmd.AddCommand(lastTagCmd)
 	lastTagCmd.Flags().StringVarP(&lastTagService, "service", "s", "", "service name to check")
+	lastTagCmd.Flags().BoolVar(&lastTagRemote, "remote", false, "read the tag from the remote with git ls-remote")
 }
 
 func runLastTag(cmd *cobra.Command, args []string) {
@@ -42,28 +48,30 @@ func runLastTag(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	var projectPath string
+	// Resolve the service with the config of its repository merged
+	scope, err := resolveServiceScope(lastTagService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Available services:\n")
+		suggestProjects()
+		os.Exit(1)
+	}
 
 	// If no service specified, use current working directory
-	if lastTagService == "" {
+	projectPath := scope.Dir
+	if projectPath == "" {
 		projectPath = "." // Current working directory
-	} else {
-		// Make sure config is loaded when service is specified
-		initConfig()
-
-		// Find the project path for the specified service
-		projectPath = findProjectPath(lastTagService)
-		if projectPath == "" {
-			fmt.Fprintf(os.Stderr, "Error: service '%s' not found in configuration.\n", lastTagService)
-			fmt.Fprintf(os.Stderr, "Available services:\n")
-			suggestProjects()
-			os.Exit(1)
-		}
 	}
 
-	// Get last tag for environment from the specific project directory (or current directory)
-	// Note: We don't include the service name in the tag pattern since tags are in format: env_version-release
-	lastTag, lastComment, err := utils.FindLastTagAndCommentInDir(environment, "?", "", projectPath)
+	var lastTag, lastComment string
+
+	if lastTagRemote {
+		lastTag, lastComment, err = findLastRemoteTag(environment, projectPath)
+	} else {
+		// Get last tag for environment from the specific project directory (or current directory)
+		// Note: We don't include the service name in the tag pattern since tags are in format: env_version-release
+		lastTag, lastComment, err = utils.FindLastTagAndCommentInDir(environment, "?", "", projectPath)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error finding last tag in %s: %v\n", projectPath, err)
 		os.Exit(1)
@@ -80,52 +88,65 @@ func runLastTag(cmd *cobra.Command, args []string) {
 	}
 }
 
-// findProjectPath finds the path for a given service name from the config
-func findProjectPath(serviceName string) string {
-	projects := viper.Get("projects")
-	if projects == nil {
-		return ""
+// findLastRemoteTag finds the newest tag of an environment on the service's
+// remote, with its comment when the tag was fetched
+func findLastRemoteTag(environment, projectPath string) (string, string, error) {
+	scope := serviceScope{Name: lastTagService, Dir: projectPath}
+	remote := tagRemotes(lastTagService, "")[0]
+
+	tags, err := environmentTags(scope, environment, lastTagService, remote)
+	if err != nil || len(tags) == 0 {
+		return "", "", err
 	}
 
-	projectsList, ok := projects.([]interface{})
-	if !ok {
+	comment := fmt.Sprintf("(on %s, not fetched)", remote)
+	if tagsFetched(scope.git, tags[0]) {
+		comment, _ = scope.git(fmt.Sprintf("git tag -l --format='%%(contents:subject)' %s", tags[0]))
+	}
+	return tags[0], comment, nil
+}
+
+// findProjectPath finds the path for a given service name from the config. It
+// is empty when the service is not configured or has no path.
+func findProjectPath(serviceName string) string {
+	project := findProject(serviceName)
+	if project == nil {
 		return ""
 	}
 
-	for _, proj := range projectsList {
-		projMap, ok := proj.(map[string]interface{})
-		if !ok {
-			continue
-		}
+	return project.Path
+}
+
+// findProject finds the configuration entry for a given service name or alias
+func findProject(serviceName string) *Project {
+	cfg := config.Config{Projects: loadedProjects()}
+	return cfg.FindProject(serviceName)
+}
 
-		name := getProjectStringValue(projMap, "name")
-		path := getProjectStringValue(projMap, "path")
+// loadedProjects decodes the projects of the loaded configuration. Invalid
+// project data yields no projects; 'esh-cli config validate' reports it.
+func loadedProjects() []Project {
+	var projects []Project
+	if err := viper.UnmarshalKey("projects", &projects); err != nil {
+		return nil
+	}
+	return projects
+}
 
-		// Match service name with project name
-		if strings.EqualFold(name, serviceName) {
-			return path
-		}
+// findProjectPaths returns the path globs a service is restricted to in a monorepo
+func findProjectPaths(serviceName string) []string {
+	project := findProject(serviceName)
+	if project == nil {
+		return nil
 	}
 
-	return ""
+	return project.Paths
 }
 
 // suggestProjects shows available projects to the user
 func suggestProjects() {
-	projects := viper.Get("projects")
-	if projects == nil {
-		fmt.Println("❌ No projects found in configuration.")
-		fmt.Println("Run 'esh-cli init' to discover projects automatically.")
-		return
-	}
-
-	projectsList, ok := projects.([]interface{})
-	if !ok {
-		fmt.Fprintf(os.Stderr, "Error: Invalid projects data in configuration\n")
-		return
-	}
-
-	if len(projectsList) == 0 {
+	projects := loadedProjects()
+	if len(projects) == 0 {
 		fmt.Println("❌ No projects found in configuration.")
 		fmt.Println("Run 'esh-cli init' to discover projects automatically.")
 		return
@@ -135,28 +156,9 @@ func suggestProjects() {
 	fmt.Println("Please specify a service using --service or -s flag:")
 	fmt.Println()
 
-	for _, proj := range projectsList {
-		projMap, ok := proj.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		name := getProjectStringValue(projMap, "name")
-		projectType := getProjectStringValue(projMap, "type")
-
-		fmt.Printf("  • %s (%s)\n", name, projectType)
+	for _, project := range projects {
+		fmt.Printf("  • %s (%s)\n", project.Name, orUnknown(project.Type))
 	}
 
-	fmt.Printf("\nExample: esh-cli last-tag %s --service %s\n",
-		"stg6", getProjectStringValue(projectsList[0].(map[string]interface{}), "name"))
-}
-
-// getProjectStringValue safely extracts string value from map
-func getProjectStringValue(m map[string]interface{}, key string) string {
-	if val, exists := m[key]; exists {
-		if str, ok := val.(string); ok {
-			return str
-		}
-	}
-	return "unknown"
+	fmt.Printf("\nExample: esh-cli last-tag %s --service %s\n", "stg6", projects[0].Name)
 }
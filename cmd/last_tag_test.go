@@ -329,54 +329,26 @@ func TestSuggestProjects(t *testing.T) {
 	suggestProjects()
 }
 
-func TestGetProjectStringValue(t *testing.T) {
-	// Test the getProjectStringValue helper function
-	tests := []struct {
-		name     string
-		project  map[string]interface{}
-		key      string
-		expected string
-	}{
-		{
-			name: "valid map with string value",
-			project: map[string]interface{}{
-				"name": "test-service",
-				"path": "/test/path",
-			},
-			key:      "name",
-			expected: "test-service",
-		},
-		{
-			name: "valid map with missing key",
-			project: map[string]interface{}{
-				"name": "test-service",
-			},
-			key:      "path",
-			expected: "unknown",
-		},
-		{
-			name: "non-string value",
-			project: map[string]interface{}{
-				"count": 123,
-			},
-			key:      "count",
-			expected: "unknown",
-		},
-		{
-			name:     "empty map",
-			project:  map[string]interface{}{},
-			key:      "name",
-			expected: "unknown",
-		},
+func TestFindProject(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "api", "path": "/repo/api", "aliases": []interface{}{"a"}},
+		map[string]interface{}{"name": "worker", "type": "go"},
+	})
+
+	if project := findProject("API"); project == nil || project.Name != "api" {
+		t.Errorf("findProject(API) = %+v, want api", project)
+	}
+	if project := findProject("a"); project == nil || project.Name != "api" {
+		t.Errorf("findProject(a) = %+v, want api by its alias", project)
+	}
+	if project := findProject("missing"); project != nil {
+		t.Errorf("findProject(missing) = %+v, want nil", project)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getProjectStringValue(tt.project, tt.key)
-			if result != tt.expected {
-				t.Errorf("getProjectStringValue(%v, %q) = %q, expected %q",
-					tt.project, tt.key, result, tt.expected)
-			}
-		})
+	// A project without a path has no directory to run in
+	if path := findProjectPath("worker"); path != "" {
+		t.Errorf("findProjectPath(worker) = %q, want empty", path)
 	}
 }
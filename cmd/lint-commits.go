@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Exit codes of lint-commits
+const (
+	lintExitViolations = 1
+	lintExitError      = 2
+)
+
+var (
+	lintMessageFile string
+	lintService     string
+)
+
+// lintedCommit is a commit message with the rules it breaks
+type lintedCommit struct {
+	Hash       string
+	Header     string
+	Violations []utils.LintViolation
+}
+
+// lintCommitsCmd represents the lint-commits command
+var lintCommitsCmd = &cobra.Command{
+	Use:   "lint-commits [range]",
+	Short: "Check commit messages against the conventional commit format",
+	Long: `Check that commit messages follow the conventional commit format used by
+changelog and bump-version --auto.
+
+Each message must have a 'type(scope)!: subject' header using a type (or alias)
+from the changelog.types configuration, a lower case scope, a subject without a
+trailing period, and a blank line before the body. Merge, dependency and
+fixup!/squash! commits are skipped.
+
+Without a range, the HEAD commit is checked. With --message-file, the message
+being written is checked, which is what a commit-msg hook needs.
+
+Exit codes: 0 when all messages are valid, 1 when violations are found,
+2 when the messages cannot be read.`,
+	Example: `  esh-cli lint-commits                           # Check the HEAD commit
+  esh-cli lint-commits main..HEAD                # Check the commits of a branch
+  esh-cli lint-commits stg6_1.2.0-1..HEAD        # Check everything since a tag
+  esh-cli lint-commits --message-file "$1"       # commit-msg hook mode`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runLintCommits,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCommitsCmd)
+
+	lintCommitsCmd.Flags().StringVar(&lintMessageFile, "message-file", "", "Check the commit message in this file (commit-msg hook mode)")
+	lintCommitsCmd.Flags().StringVarP(&lintService, "service", "s", "", "Check commits in the service's repository touching its paths")
+}
+
+func runLintCommits(cmd *cobra.Command, args []string) {
+	if lintMessageFile != "" && len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --message-file cannot be combined with a commit range\n")
+		os.Exit(lintExitError)
+	}
+
+	taxonomy, err := loadCommitTaxonomy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(lintExitError)
+	}
+
+	rules, err := loadLintRules()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(lintExitError)
+	}
+
+	var results []lintedCommit
+	if lintMessageFile != "" {
+		data, err := os.ReadFile(lintMessageFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read commit message: %v\n", err)
+			os.Exit(lintExitError)
+		}
+		results = []lintedCommit{lintCommitMessage("", string(data), taxonomy, rules)}
+	} else {
+		scope, err := resolveServiceScope(lintService)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(lintExitError)
+		}
+
+		revRange := "-1 HEAD"
+		if len(args) == 1 {
+			revRange = args[0]
+		}
+
+		commits, err := utils.GetCommitMessagesInDir(revRange, scope.Dir, scope.Paths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(lintExitError)
+		}
+
+		for _, commit := range commits {
+			results = append(results, lintCommitMessage(commit.Hash, commit.Message, taxonomy, rules))
+		}
+	}
+
+	invalid := printLintResults(results)
+	if invalid > 0 {
+		os.Exit(lintExitViolations)
+	}
+}
+
+// loadLintRules reads the lint section of the configuration
+func loadLintRules() (utils.LintRules, error) {
+	var rules utils.LintRules
+	if err := viper.UnmarshalKey("lint", &rules); err != nil {
+		return rules, fmt.Errorf("invalid lint configuration: %v", err)
+	}
+	return rules, nil
+}
+
+// lintCommitMessage lints one commit message
+func lintCommitMessage(hash, message string, taxonomy *utils.CommitTaxonomy, rules utils.LintRules) lintedCommit {
+	header := strings.SplitN(utils.CleanCommitMessage(message), "\n", 2)[0]
+	return lintedCommit{
+		Hash:       hash,
+		Header:     header,
+		Violations: taxonomy.Lint(message, rules),
+	}
+}
+
+// printLintResults reports the violations and returns the number of invalid messages
+func printLintResults(results []lintedCommit) int {
+	invalid := 0
+	for _, result := range results {
+		if len(result.Violations) == 0 {
+			continue
+		}
+		invalid++
+
+		if result.Hash != "" {
+			fmt.Fprintf(os.Stderr, "✗ %s %s\n", shortHash(result.Hash), result.Header)
+		} else {
+			fmt.Fprintf(os.Stderr, "✗ %s\n", result.Header)
+		}
+		for _, violation := range result.Violations {
+			fmt.Fprintf(os.Stderr, "    %s\n", violation)
+		}
+	}
+
+	if invalid > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d of %s failed conventional commit checks\n",
+			invalid, pluralize(len(results), "commit message"))
+		return invalid
+	}
+
+	fmt.Printf("✓ %s valid\n", pluralize(len(results), "commit message"))
+	return 0
+}
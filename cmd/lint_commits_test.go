@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLintCommitsCmdCreation(t *testing.T) {
+	if lintCommitsCmd == nil {
+		t.Fatal("lintCommitsCmd should not be nil")
+	}
+
+	if !strings.HasPrefix(lintCommitsCmd.Use, "lint-commits") {
+		t.Errorf("Expected lintCommitsCmd.Use to start with 'lint-commits', got '%s'", lintCommitsCmd.Use)
+	}
+
+	for _, flagName := range []string{"message-file", "service"} {
+		if lintCommitsCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Flag '%s' should be defined", flagName)
+		}
+	}
+
+	if err := lintCommitsCmd.Args(lintCommitsCmd, []string{"a..b", "c"}); err == nil {
+		t.Error("Expected error with 2 arguments for MaximumNArgs(1)")
+	}
+}
+
+func TestLoadLintRules(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("lint", map[string]interface{}{
+		"max_header_length": 50,
+		"scopes":            []string{"api", "web"},
+		"require_scope":     true,
+	})
+
+	rules, err := loadLintRules()
+	if err != nil {
+		t.Fatalf("loadLintRules unexpected error: %v", err)
+	}
+	if rules.MaxHeaderLength != 50 || !rules.RequireScope || len(rules.Scopes) != 2 {
+		t.Errorf("loadLintRules() = %+v, want configured rules", rules)
+	}
+}
+
+func TestLintCommitMessage(t *testing.T) {
+	taxonomy := utils.DefaultCommitTaxonomy()
+
+	result := lintCommitMessage("0123456789abcdef", "# comment\nAdd login\n\nBody", taxonomy, utils.LintRules{})
+	if result.Header != "Add login" {
+		t.Errorf("Expected header without comments, got %q", result.Header)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "format" {
+		t.Errorf("Expected a format violation, got %v", result.Violations)
+	}
+
+	if invalid := printLintResults([]lintedCommit{result}); invalid != 1 {
+		t.Errorf("printLintResults() = %d, want 1", invalid)
+	}
+}
+
+func TestRunLintCommitsMessageFileExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		wantCode int
+	}{
+		{"valid", "feat(api): add login\n", 0},
+		{"invalid", "added login\n", lintExitViolations},
+		{"missing", "", lintExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if os.Getenv("BE_CRASHER") == "1" {
+				lintMessageFile = os.Getenv("LINT_MESSAGE_FILE")
+				runLintCommits(lintCommitsCmd, nil)
+				return
+			}
+
+			messageFile := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+			if tt.message != "" {
+				if err := os.WriteFile(messageFile, []byte(tt.message), 0644); err != nil {
+					t.Fatalf("Failed to write message file: %v", err)
+				}
+			}
+
+			subCmd := exec.Command(os.Args[0], "-test.run=TestRunLintCommitsMessageFileExitCodes/"+tt.name)
+			subCmd.Env = append(os.Environ(), "BE_CRASHER=1", "LINT_MESSAGE_FILE="+messageFile)
+			err := subCmd.Run()
+
+			code := 0
+			if e, ok := err.(*exec.ExitError); ok {
+				code = e.ExitCode()
+			}
+			if code != tt.wantCode {
+				t.Errorf("Expected exit code %d, got %d", tt.wantCode, code)
+			}
+		})
+	}
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"esh-cli/pkg/config"
+	"esh-cli/pkg/utils"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -13,14 +18,79 @@ var projectsCmd = &cobra.Command{
 	Use:   "projects",
 	Short: "List discovered projects",
 	Long: `List all projects that have been discovered and saved in the configuration.
-This shows projects found during initialization or manually added.`,
+This shows projects found during initialization or manually added.
+
+The subcommands add, remove, rename and change projects in the user config
+without touching its other settings.`,
 	Example: `  esh-cli projects - list all discovered projects
-  esh-cli projects --config /path/to/config.yaml - use specific config file`,
-	Run: runProjects,
+  esh-cli projects --config /path/to/config.yaml - use specific config file
+  esh-cli projects add api ~/workspace/api --type golang
+  esh-cli projects add worker ~/workspace/platform --paths "services/worker/**"
+  esh-cli projects set api remote upstream
+  esh-cli projects set payments-ledger-service aliases "[ledger]"
+  esh-cli projects rename api payments-api
+  esh-cli projects remove worker`,
+	Args: cobra.NoArgs,
+	Run:  runProjects,
+}
+
+var projectsAddCmd = &cobra.Command{
+	Use:   "add <name> <path>",
+	Short: "Add a project to the configuration",
+	Long: `Add a project to the user config. The path must be inside a git repository
+and the name must not be taken. The type is detected from the project files
+unless --type is given.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runProjectsAdd,
+}
+
+var projectsRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a project from the configuration",
+	Args:    cobra.ExactArgs(1),
+	Run:     runProjectsRemove,
+}
+
+var projectsRenameCmd = &cobra.Command{
+	Use:   "rename <name> <new-name>",
+	Short: "Rename a project",
+	Long: `Rename a project. Tags already created for the service keep the old name
+as their prefix, so the history of the new name starts empty.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runProjectsRename,
+}
+
+var projectsSetCmd = &cobra.Command{
+	Use:   "set <name> <key> <value>",
+	Short: "Change a setting of a project",
+	Long: `Change a setting of a project, such as path, type, aliases, paths, remote,
+push_remotes, initial_development or initial_version. Values are parsed as
+YAML, so "[a, b]" is a list. A new path must be inside a git repository.`,
+	Example: `  esh-cli projects set api path ~/workspace/api-v2
+  esh-cli projects set api push_remotes "[origin, mirror]"`,
+	Args: cobra.ExactArgs(3),
+	Run:  runProjectsSet,
 }
 
+var (
+	projectType    string
+	projectPaths   []string
+	projectAliases []string
+	projectRemote  string
+)
+
 func init() {
 	rootCmd.AddCommand(projectsCmd)
+	projectsCmd.AddCommand(projectsAddCmd)
+	projectsCmd.AddCommand(projectsRemoveCmd)
+	projectsCmd.AddCommand(projectsRenameCmd)
+	projectsCmd.AddCommand(projectsSetCmd)
+
+	projectsAddCmd.Flags().StringVar(&projectType, "type", "", "Project type (detected from the project files by default)")
+	projectsAddCmd.Flags().StringSliceVar(&projectPaths, "paths", nil, "Path globs of a service in a monorepo")
+	projectsAddCmd.Flags().StringSliceVar(&projectAliases, "aliases", nil, "Short names --service accepts for the project")
+	projectsAddCmd.Flags().StringVar(&projectRemote, "remote", "", "Remote the project's tags are pushed to")
 }
 
 func runProjects(cmd *cobra.Command, args []string) {
@@ -28,43 +98,50 @@ func runProjects(cmd *cobra.Command, args []string) {
 	initConfig()
 
 	// Get projects from config
-	projects := viper.Get("projects")
-	if projects == nil {
-		fmt.Println("❌ No projects found in configuration.")
-		fmt.Println("Run 'esh-cli init' to discover projects automatically.")
-		return
-	}
-
-	// Type assertion to handle viper's interface{} return
-	projectsList, ok := projects.([]interface{})
-	if !ok {
-		fmt.Fprintf(os.Stderr, "Error: Invalid projects data in configuration\n")
+	var projects []Project
+	if err := viper.UnmarshalKey("projects", &projects); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid projects data in configuration: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Run 'esh-cli config validate' for details.")
 		os.Exit(1)
 	}
 
-	if len(projectsList) == 0 {
+	if len(projects) == 0 {
 		fmt.Println("❌ No projects found in configuration.")
 		fmt.Println("Run 'esh-cli init' to discover projects automatically.")
 		return
 	}
 
 	// Display header
-	fmt.Printf("📁 Found %d configured projects:\n\n", len(projectsList))
+	fmt.Printf("📁 Found %d configured projects:\n\n", len(projects))
 
 	// Display each project
-	for i, proj := range projectsList {
-		projMap, ok := proj.(map[string]interface{})
-		if !ok {
-			continue
+	for i, project := range projects {
+		fmt.Printf("  %d. %s\n", i+1, project.Name)
+		fmt.Printf("     Path: %s\n", project.Path)
+		fmt.Printf("     Type: %s\n", orUnknown(project.Type))
+		if len(project.Paths) > 0 {
+			fmt.Printf("     Paths: %s\n", strings.Join(project.Paths, ", "))
+		}
+		if len(project.Aliases) > 0 {
+			fmt.Printf("     Aliases: %s\n", strings.Join(project.Aliases, ", "))
 		}
+		if project.Remote != "" {
+			fmt.Printf("     Remote: %s\n", project.Remote)
+		}
+		fmt.Println()
+	}
 
-		name := getStringValue(projMap, "name")
-		path := getStringValue(projMap, "path")
-		projectType := getStringValue(projMap, "type")
+	if groups := viper.GetStringMapStringSlice("groups"); len(groups) > 0 {
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
-		fmt.Printf("  %d. %s\n", i+1, name)
-		fmt.Printf("     Path: %s\n", path)
-		fmt.Printf("     Type: %s\n", projectType)
+		fmt.Println("👥 Groups:")
+		for _, name := range names {
+			fmt.Printf("  %s: %s\n", name, strings.Join(groups[name], ", "))
+		}
 		fmt.Println()
 	}
 
@@ -81,12 +158,230 @@ func runProjects(cmd *cobra.Command, args []string) {
 	}
 }
 
-// getStringValue safely extracts string value from map
-func getStringValue(m map[string]interface{}, key string) string {
-	if val, exists := m[key]; exists {
-		if str, ok := val.(string); ok {
-			return str
+func runProjectsAdd(cmd *cobra.Command, args []string) {
+	name := args[0]
+	path, err := projectRepositoryPath(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	project := map[string]interface{}{
+		"name": name,
+		"path": path,
+		"type": projectType,
+	}
+	if projectType == "" {
+		project["type"] = determineProjectType(path)
+	}
+	if url := projectRemoteURL(path); url != "" {
+		project["remote_url"] = url
+	}
+	if len(projectPaths) > 0 {
+		project["paths"] = projectPaths
+	}
+	if len(projectAliases) > 0 {
+		project["aliases"] = projectAliases
+	}
+	if projectRemote != "" {
+		project["remote"] = projectRemote
+	}
+
+	updateConfigFile(getConfigFilePath(), func(raw map[string]interface{}) error {
+		projects, err := rawProjects(raw)
+		if err != nil {
+			return err
+		}
+		if err := checkProjectName(projects, name); err != nil {
+			return err
+		}
+		raw["projects"] = append(projects, project)
+		return nil
+	})
+
+	fmt.Printf("✅ Added %s (%s, %s)\n", name, path, project["type"])
+	warnShadowedProjects()
+}
+
+func runProjectsRemove(cmd *cobra.Command, args []string) {
+	updateConfigFile(getConfigFilePath(), func(raw map[string]interface{}) error {
+		projects, index, err := rawProject(raw, args[0])
+		if err != nil {
+			return err
+		}
+		updateGroupMembers(raw, projects[index].(map[string]interface{}), "")
+		raw["projects"] = append(projects[:index], projects[index+1:]...)
+		return nil
+	})
+
+	fmt.Printf("✅ Removed %s\n", args[0])
+	warnShadowedProjects()
+}
+
+func runProjectsRename(cmd *cobra.Command, args []string) {
+	name, newName := args[0], args[1]
+	updateConfigFile(getConfigFilePath(), func(raw map[string]interface{}) error {
+		projects, index, err := rawProject(raw, name)
+		if err != nil {
+			return err
+		}
+		// Changing only the case of a name does not collide with itself
+		if !strings.EqualFold(name, newName) {
+			if err := checkProjectName(projects, newName); err != nil {
+				return err
+			}
+		}
+		project := projects[index].(map[string]interface{})
+		updateGroupMembers(raw, project, newName)
+		project["name"] = newName
+		return nil
+	})
+
+	fmt.Printf("✅ Renamed %s to %s\n", name, newName)
+	fmt.Printf("💡 Existing tags are prefixed %s_, new tags will be prefixed %s_\n", name, newName)
+	warnShadowedProjects()
+}
+
+func runProjectsSet(cmd *cobra.Command, args []string) {
+	name, key := args[0], args[1]
+	if key == "name" {
+		fmt.Fprintf(os.Stderr, "Error: use 'esh-cli projects rename' to change the name\n")
+		os.Exit(1)
+	}
+
+	value := config.ParseValue(args[2])
+	if key == "path" {
+		path, err := projectRepositoryPath(args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		value = path
+	}
+
+	updateConfigFile(getConfigFilePath(), func(raw map[string]interface{}) error {
+		_, index, err := rawProject(raw, name)
+		if err != nil {
+			return err
+		}
+		return config.SetValue(raw, fmt.Sprintf("projects.%d.%s", index, key), value)
+	})
+
+	fmt.Printf("✅ Set %s of %s\n", key, name)
+	warnShadowedProjects()
+}
+
+// projectRepositoryPath returns the absolute path of a project directory,
+// which must be inside a git repository
+func projectRepositoryPath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", path)
+	}
+	if _, err := utils.CmdInDir("git rev-parse --show-toplevel", path); err != nil {
+		return "", fmt.Errorf("%s is not in a git repository", path)
+	}
+	return path, nil
+}
+
+// rawProjects returns the projects list of a raw config
+func rawProjects(raw map[string]interface{}) ([]interface{}, error) {
+	switch projects := raw["projects"].(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return projects, nil
+	default:
+		return nil, fmt.Errorf("projects must be a list, run 'esh-cli config validate'")
+	}
+}
+
+// rawProject returns the projects list of a raw config and the index of the
+// project with a name or alias
+func rawProject(raw map[string]interface{}, name string) ([]interface{}, int, error) {
+	projects, err := rawProjects(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, item := range projects {
+		if project, ok := item.(map[string]interface{}); ok && rawProjectMatches(project, name) {
+			return projects, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("project '%s' not found in %s", name, getConfigFilePath())
+}
+
+// rawProjectMatches reports whether a name is a raw project's name or alias
+func rawProjectMatches(project map[string]interface{}, name string) bool {
+	return config.DecodeProject(project).Matches(name)
+}
+
+// updateGroupMembers replaces the group members naming a project, by name or
+// alias, with a new name, or drops them when the new name is empty
+func updateGroupMembers(raw map[string]interface{}, project map[string]interface{}, newName string) {
+	groups, ok := raw["groups"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for group, value := range groups {
+		members, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		updated := make([]interface{}, 0, len(members))
+		for _, member := range members {
+			if name, ok := member.(string); ok && rawProjectMatches(project, name) {
+				if newName == "" {
+					continue
+				}
+				member = newName
+			}
+			updated = append(updated, member)
+		}
+		groups[group] = updated
+	}
+}
+
+// checkProjectName checks that a new project name is usable as a config key
+// segment and not taken by another project
+func checkProjectName(projects []interface{}, name string) error {
+	if name == "" || strings.ContainsAny(name, ". \t") {
+		return fmt.Errorf("invalid project name %q, names cannot contain dots or spaces", name)
+	}
+	for _, item := range projects {
+		project := config.DecodeProject(item)
+		if strings.EqualFold(project.Name, name) {
+			return fmt.Errorf("project '%s' already exists at %s", name, orUnknown(project.Path))
+		}
+		for _, alias := range project.Aliases {
+			if strings.EqualFold(alias, name) {
+				return fmt.Errorf("'%s' is already an alias of project '%s'", name, project.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// warnShadowedProjects warns when a repository config replaces the projects
+// of the user config, which the projects commands change
+func warnShadowedProjects() {
+	for _, source := range configSources {
+		if source.Kind != "repo" {
+			continue
+		}
+		if raw, err := config.ReadFile(source.Path); err == nil && raw["projects"] != nil {
+			fmt.Printf("⚠️  %s defines projects, which replace the projects of the user config here\n", source.Path)
 		}
 	}
-	return "unknown"
+}
+
+// orUnknown shows "unknown" for a value that is not set
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
 }
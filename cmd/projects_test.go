@@ -2,6 +2,10 @@ package cmd
 
 import (
 	"bytes"
+	"esh-cli/pkg/config"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -54,30 +58,6 @@ func TestRunProjectsExecution(t *testing.T) {
 	runProjects(cmd, []string{})
 }
 
-func TestGetStringValue(t *testing.T) {
-	tests := []struct {
-		name     string
-		m        map[string]interface{}
-		key      string
-		expected string
-	}{
-		{"string value", map[string]interface{}{"test": "value"}, "test", "value"},
-		{"missing key", map[string]interface{}{"other": "value"}, "test", "unknown"},
-		{"non-string value", map[string]interface{}{"test": 123}, "test", "unknown"},
-		{"nil map", nil, "test", "unknown"},
-		{"empty map", map[string]interface{}{}, "test", "unknown"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getStringValue(tt.m, tt.key)
-			if result != tt.expected {
-				t.Errorf("getStringValue(%v, %q) = %q, want %q", tt.m, tt.key, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestProjectsCmdHelp(t *testing.T) {
 	// Test that help can be generated without error
 	if projectsCmd.Long == "" {
@@ -89,3 +69,81 @@ func TestProjectsCmdHelp(t *testing.T) {
 		t.Error("Examples should not be empty")
 	}
 }
+
+func TestProjectsAddSetRenameRemove(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "api")
+	gitIn(t, root, "init", "-q", repo)
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module api\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, ".esh-cli.yaml")
+	if err := os.WriteFile(path, []byte("remote: upstream\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	original := cfgFile
+	cfgFile = path
+	defer func() { cfgFile = original }()
+
+	captureStdout(t, func() {
+		projectPaths = []string{"services/api/**"}
+		defer func() { projectPaths = nil }()
+		runProjectsAdd(projectsAddCmd, []string{"api", repo})
+		runProjectsSet(projectsSetCmd, []string{"API", "push_remotes", "[origin, mirror]"})
+		runProjectsRename(projectsRenameCmd, []string{"api", "payments"})
+	})
+
+	cfg, errs := config.Load(path)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if cfg.Remote != "upstream" {
+		t.Errorf("remote = %q, other keys should be kept", cfg.Remote)
+	}
+	project := cfg.FindProject("payments")
+	if project == nil {
+		t.Fatalf("renamed project not found in %+v", cfg.Projects)
+	}
+	if project.Path != repo || project.Type != "golang" {
+		t.Errorf("project = %+v, want path %s and the detected type", project, repo)
+	}
+	if !reflect.DeepEqual(project.Paths, []string{"services/api/**"}) || !reflect.DeepEqual(project.PushRemotes, []string{"origin", "mirror"}) {
+		t.Errorf("project = %+v, want the paths and push_remotes set", project)
+	}
+
+	captureStdout(t, func() { runProjectsRemove(projectsRemoveCmd, []string{"payments"}) })
+	if cfg, _ := config.Load(path); len(cfg.Projects) != 0 {
+		t.Errorf("projects = %+v, want none after remove", cfg.Projects)
+	}
+}
+
+func TestCheckProjectName(t *testing.T) {
+	projects := []interface{}{map[string]interface{}{"name": "api", "path": "/src/api"}}
+
+	if err := checkProjectName(projects, "worker"); err != nil {
+		t.Errorf("checkProjectName(worker) = %v", err)
+	}
+	for _, name := range []string{"API", "my.api", "my api", ""} {
+		if err := checkProjectName(projects, name); err == nil {
+			t.Errorf("checkProjectName(%q) should fail", name)
+		}
+	}
+}
+
+func TestProjectRepositoryPath(t *testing.T) {
+	root := t.TempDir()
+	gitIn(t, root, "init", "-q", "repo")
+	if err := os.MkdirAll(filepath.Join(root, "repo", "services"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, err := projectRepositoryPath(filepath.Join(root, "repo", "services")); err != nil || path != filepath.Join(root, "repo", "services") {
+		t.Errorf("projectRepositoryPath() = %q, %v", path, err)
+	}
+	if _, err := projectRepositoryPath(root); err == nil {
+		t.Error("projectRepositoryPath() should reject a directory outside a git repository")
+	}
+	if _, err := projectRepositoryPath(filepath.Join(root, "missing")); err == nil {
+		t.Error("projectRepositoryPath() should reject a missing directory")
+	}
+}
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseFrom    string
+	releaseBump    string
+	releaseEnv     string
+	releaseService string
+	releasePreview bool
+	releaseRemote  string
+)
+
+// releaseBranch is a release_X.Y branch found locally or on the remote
+type releaseBranch struct {
+	Name   string
+	Line   string
+	Local  bool
+	Remote bool
+}
+
+// releaseBranchCmd represents the release-branch command
+var releaseBranchCmd = &cobra.Command{
+	Use:   "release-branch",
+	Short: "Create and list release branches",
+	Long: `Create and list the release_X.Y branches hot fixes are tagged from.
+
+A release branch is named after the MAJOR.MINOR of the version it releases,
+which is the format add-tag --hot-fix expects.`,
+}
+
+var releaseBranchCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create, push and tag the next release branch",
+	Long: `Create the next release_X.Y branch and tag its branch point.
+
+The next version is the highest version across all environments bumped by
+--bump (minor by default). The branch is created from --from without checking
+it out, pushed to the remote, and its branch point is tagged env_X.Y.0-1 for the
+--env environment, so later hot fixes can be tagged with add-tag --hot-fix.`,
+	Example: `  esh-cli release-branch create                       # release_X.Y from main, tagged for dev
+  esh-cli release-branch create --from develop --bump major
+  esh-cli release-branch create --env stg6 --preview  # Show what would be created
+  esh-cli release-branch create --service api`,
+	Args: cobra.NoArgs,
+	Run:  runReleaseBranchCreate,
+}
+
+var releaseBranchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List release branches with their latest hot fix tags",
+	Args:  cobra.NoArgs,
+	Run:   runReleaseBranchList,
+}
+
+func init() {
+	rootCmd.AddCommand(releaseBranchCmd)
+	releaseBranchCmd.AddCommand(releaseBranchCreateCmd)
+	releaseBranchCmd.AddCommand(releaseBranchListCmd)
+
+	releaseBranchCreateCmd.Flags().StringVar(&releaseFrom, "from", "main", "Branch or commit to create the release branch from")
+	releaseBranchCreateCmd.Flags().StringVar(&releaseBump, "bump", string(utils.BumpMinor), "Version bump of the release (major or minor)")
+	releaseBranchCreateCmd.Flags().StringVarP(&releaseEnv, "env", "e", "dev", "Environment to tag the branch point for")
+	releaseBranchCreateCmd.Flags().BoolVar(&releasePreview, "preview", false, "Show what would be created without executing")
+
+	for _, cmd := range []*cobra.Command{releaseBranchCreateCmd, releaseBranchListCmd} {
+		cmd.Flags().StringVarP(&releaseService, "service", "s", "", "Service name for tagging")
+		cmd.Flags().StringVar(&releaseRemote, "remote", "", "Remote the release branches are pushed to (default: remote setting or origin)")
+	}
+}
+
+func runReleaseBranchCreate(cmd *cobra.Command, args []string) {
+	bumpType := utils.BumpType(releaseBump)
+	if bumpType != utils.BumpMajor && bumpType != utils.BumpMinor {
+		fmt.Fprintf(os.Stderr, "Error: --bump must be major or minor, got: %s\n", releaseBump)
+		os.Exit(1)
+	}
+
+	if !utils.ContainsString(utils.ENVS, releaseEnv) {
+		fmt.Fprintf(os.Stderr, "Error: invalid environment '%s'. Valid environments: %v\n",
+			releaseEnv, utils.ENVS)
+		os.Exit(1)
+	}
+
+	scope, err := resolveServiceScope(releaseService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	versions, err := utils.LatestEnvironmentVersions(releaseService, scope.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error finding environment versions: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Breaking releases in 0.x bump minor under the initial development policy
+	if highest, found := utils.HighestEnvironmentVersion(versions); found && initialDevelopmentPolicy(releaseService) {
+		if lowered, ok := utils.ApplyInitialDevelopment(highest.Version, bumpType); ok {
+			bumpType = lowered
+			fmt.Printf("Initial development: %s is below 1.0.0, bumping minor instead\n", highest.Version)
+		}
+	}
+
+	base, version, err := nextReleaseVersion(versions, bumpType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	branch, _ := utils.ReleaseBranchName(version)
+	tag := fmt.Sprintf("%s-1", utils.TagPrefix(releaseEnv, version, releaseService))
+
+	remotes := tagRemotes(releaseService, releaseRemote)
+	if err := checkReleaseBranchFree(scope, branch, tag, remotes[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	commit, err := scope.git(fmt.Sprintf("git rev-parse --verify %s^{commit}", releaseFrom))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", releaseFrom, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🌿 Release Branch\n")
+	fmt.Printf("Highest version: %s (%s)\n", base.Version, base.Tag)
+	fmt.Printf("Release:         %s (%s bump)\n", version, bumpType)
+	fmt.Printf("Branch:          %s from %s (%s)\n", branch, releaseFrom, shortHash(commit))
+	fmt.Printf("Tag:             %s\n", tag)
+
+	if releasePreview {
+		fmt.Printf("\nTo create this release branch, run the same command without --preview\n")
+		return
+	}
+
+	if utils.Ask(fmt.Sprintf("Create and push %s and %s? (y/n)", branch, tag)) != "y" {
+		fmt.Println("Operation cancelled")
+		os.Exit(0)
+	}
+
+	if err := createReleaseBranch(scope, branch, tag, commit, remotes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Successfully created %s and tagged its branch point %s\n", branch, tag)
+	fmt.Printf("\n💡 Tag hot fixes from the branch with:\n")
+	fmt.Printf("  git checkout %s\n", branch)
+	fmt.Printf("  esh-cli add-tag %s %s --hot-fix\n", releaseEnv, version)
+}
+
+// checkReleaseBranchFree fails when the release branch or its branch point
+// tag already exists locally or on the remote
+func checkReleaseBranchFree(scope serviceScope, branch, tag, remote string) error {
+	if _, err := scope.git(fmt.Sprintf("git rev-parse --verify --quiet refs/heads/%s", branch)); err == nil {
+		return fmt.Errorf("branch %s already exists", branch)
+	}
+	if existing, _ := scope.git(fmt.Sprintf("git ls-remote --heads %s %s", remote, branch)); existing != "" {
+		return fmt.Errorf("branch %s already exists on %s", branch, remote)
+	}
+	if _, err := scope.git(fmt.Sprintf("git rev-parse --verify --quiet refs/tags/%s", tag)); err == nil {
+		return fmt.Errorf("tag %s already exists", tag)
+	}
+	if existing, _ := scope.git(fmt.Sprintf("git ls-remote --tags %s refs/tags/%s", remote, tag)); existing != "" {
+		return fmt.Errorf("tag %s already exists on %s", tag, remote)
+	}
+	return nil
+}
+
+// createReleaseBranch creates and pushes the release branch and tags its
+// branch point under the tag lock. The branch and tag are checked again once
+// the lock is held, since another run may have created the release while the
+// user was asked; a branch point tag rejected by the remote is not retried
+// with another number, as it belongs to that other run's release.
+func createReleaseBranch(scope serviceScope, branch, tag, commit string, remotes []string) error {
+	return withTagLock(scope.git, func() error {
+		if err := checkReleaseBranchFree(scope, branch, tag, remotes[0]); err != nil {
+			return err
+		}
+
+		steps := []struct {
+			command string
+			failure string
+		}{
+			{fmt.Sprintf("git branch %s %s", branch, commit), "create branch"},
+			{fmt.Sprintf("git push %s %s", remotes[0], branch), "push branch"},
+		}
+		for _, step := range steps {
+			if _, err := scope.git(step.command); err != nil {
+				return fmt.Errorf("failed to %s: %v", step.failure, err)
+			}
+		}
+
+		created := func(taken string) (string, error) {
+			return "", fmt.Errorf("release %s was created by another run", branch)
+		}
+		_, err := createAndPushTag(scope.git, tag, fmt.Sprintf("Release branch %s", branch), commit, remotes, created)
+		return err
+	})
+}
+
+func runReleaseBranchList(cmd *cobra.Command, args []string) {
+	scope, err := resolveServiceScope(releaseService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	remote := tagRemotes(releaseService, releaseRemote)[0]
+	output, err := scope.git(fmt.Sprintf("git for-each-ref --format='%%(refname:short)' refs/heads/release_* refs/remotes/%s/release_*", remote))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing release branches: %v\n", err)
+		os.Exit(1)
+	}
+
+	branches := parseReleaseBranches(strings.Split(output, "\n"), remote)
+	if len(branches) == 0 {
+		fmt.Println("No release branches found")
+		return
+	}
+
+	var tags []string
+	for _, env := range utils.ENVS {
+		envTags, err := utils.ListEnvironmentTags(env, releaseService, scope.Dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		tags = append(tags, envTags...)
+	}
+
+	fmt.Printf("%-20s %-16s %s\n", "BRANCH", "WHERE", "LATEST HOT FIX")
+	for _, branch := range branches {
+		var where []string
+		if branch.Local {
+			where = append(where, "local")
+		}
+		if branch.Remote {
+			where = append(where, remote)
+		}
+
+		hotFix := utils.LatestHotFixTag(tags, branch.Line)
+		fmt.Printf("%-20s %-16s %s\n", branch.Name, strings.Join(where, ", "), orNone(hotFix))
+	}
+}
+
+// nextReleaseVersion bumps the highest version across environments and returns
+// it with the environment version it was bumped from
+func nextReleaseVersion(versions []utils.EnvironmentVersion, bumpType utils.BumpType) (utils.EnvironmentVersion, string, error) {
+	highest, found := utils.HighestEnvironmentVersion(versions)
+	if !found {
+		return highest, "", fmt.Errorf("no tags found in any environment, create the first tag with bump-version --initial")
+	}
+
+	version, err := utils.BumpSemanticVersion(highest.Version, bumpType)
+	if err != nil {
+		return highest, "", err
+	}
+	return highest, version, nil
+}
+
+// parseReleaseBranches merges local and remote release_X.Y refs into release
+// branches, newest release line first
+func parseReleaseBranches(refs []string, remote string) []releaseBranch {
+	byName := map[string]*releaseBranch{}
+	var branches []*releaseBranch
+
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		name := strings.TrimPrefix(ref, remote+"/")
+		line, ok := utils.ReleaseLine(name)
+		if !ok {
+			continue
+		}
+
+		branch, exists := byName[name]
+		if !exists {
+			branch = &releaseBranch{Name: name, Line: line}
+			byName[name] = branch
+			branches = append(branches, branch)
+		}
+		if name == ref {
+			branch.Local = true
+		} else {
+			branch.Remote = true
+		}
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		cmp, err := utils.CompareSemanticVersions(branches[i].Line+".0", branches[j].Line+".0")
+		return err == nil && cmp > 0
+	})
+
+	result := make([]releaseBranch, 0, len(branches))
+	for _, branch := range branches {
+		result = append(result, *branch)
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReleaseBranchCmdCreation(t *testing.T) {
+	for _, name := range []string{"create", "list"} {
+		found := false
+		for _, sub := range releaseBranchCmd.Commands() {
+			if sub.Name() == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("release-branch should have a %s subcommand", name)
+		}
+	}
+
+	for _, flag := range []string{"from", "bump", "env", "service", "remote", "preview"} {
+		if releaseBranchCreateCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Flag '%s' should be defined on release-branch create", flag)
+		}
+	}
+	if releaseBranchListCmd.Flags().Lookup("remote") == nil {
+		t.Error("Flag 'remote' should be defined on release-branch list")
+	}
+	if got := releaseBranchCreateCmd.Flags().Lookup("from").DefValue; got != "main" {
+		t.Errorf("--from default = %q, want main", got)
+	}
+}
+
+func TestNextReleaseVersion(t *testing.T) {
+	versions := []utils.EnvironmentVersion{
+		{Environment: "dev", Tag: "dev_1.4.2-3", Version: "1.4.2"},
+		{Environment: "production2", Tag: "production2_1.3.0-1", Version: "1.3.0"},
+	}
+
+	base, version, err := nextReleaseVersion(versions, utils.BumpMinor)
+	if err != nil || version != "1.5.0" || base.Tag != "dev_1.4.2-3" {
+		t.Errorf("nextReleaseVersion(minor) = %s, %s, %v", base.Tag, version, err)
+	}
+
+	if _, version, _ := nextReleaseVersion(versions, utils.BumpMajor); version != "2.0.0" {
+		t.Errorf("nextReleaseVersion(major) = %s, want 2.0.0", version)
+	}
+
+	if _, _, err := nextReleaseVersion(nil, utils.BumpMinor); err == nil {
+		t.Error("nextReleaseVersion without tags should fail")
+	}
+}
+
+func TestParseReleaseBranches(t *testing.T) {
+	refs := []string{
+		"release_1.2",
+		"origin/release_1.2",
+		"origin/release_1.10",
+		"release_1.9",
+		"origin/release_notes",
+		"",
+	}
+
+	want := []releaseBranch{
+		{Name: "release_1.10", Line: "1.10", Remote: true},
+		{Name: "release_1.9", Line: "1.9", Local: true},
+		{Name: "release_1.2", Line: "1.2", Local: true, Remote: true},
+	}
+
+	if got := parseReleaseBranches(refs, "origin"); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReleaseBranches() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCreateReleaseBranch(t *testing.T) {
+	work, _ := initHotfixRepo(t)
+	scope := serviceScope{Dir: work}
+	commit := gitIn(t, work, "rev-parse", "HEAD")
+
+	output := captureStdout(t, func() {
+		if err := createReleaseBranch(scope, "release_1.3", "dev_1.3.0-1", commit, []string{"origin"}); err != nil {
+			t.Errorf("createReleaseBranch unexpected error: %v", err)
+		}
+	})
+	if got := gitIn(t, work, "ls-remote", "--tags", "origin", "dev_1.3.0-1"); got == "" {
+		t.Errorf("branch point tag should be pushed:\n%s", output)
+	}
+
+	// Another run created the release while this one was asked
+	gitIn(t, work, "branch", "-D", "release_1.3")
+	gitIn(t, work, "tag", "-d", "dev_1.3.0-1")
+	err := createReleaseBranch(scope, "release_1.3", "dev_1.3.0-1", commit, []string{"origin"})
+	if err == nil || !strings.Contains(err.Error(), "already exists on origin") {
+		t.Errorf("expected the remote release branch to be detected, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(work, ".git", tagLockFile)); !os.IsNotExist(statErr) {
+		t.Error("the tag lock should be released on failure")
+	}
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"errors"
+	"esh-cli/pkg/utils"
+	"fmt"
+	"math/rand"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultRemote is the remote tags are pushed to when none is configured
+const defaultRemote = "origin"
+
+// maxTagAttempts bounds how often a tag rejected by the remote is recomputed and retried
+const maxTagAttempts = 5
+
+// Tag allocation lock, held in the git directory while a tag is computed and pushed
+const (
+	tagLockFile    = "esh-cli-tag.lock"
+	tagLockTimeout = 2 * time.Minute
+	tagLockStale   = 10 * time.Minute
+)
+
+// tagRetryBackoff is the wait before the first retry of a rejected tag. It
+// doubles with each attempt and gets random jitter, so concurrent CI jobs
+// that collided do not collide again.
+var tagRetryBackoff = 500 * time.Millisecond
+
+// gitRunner runs a git command, either in the current directory or in a service scope
+type gitRunner func(command string) (string, error)
+
+// tagRemotes returns the remote a service's tags are pushed to, followed by
+// the push_remotes mirrors. The override (a --remote flag) wins over the
+// project's remote, which wins over the top-level remote.
+func tagRemotes(service, override string) []string {
+	var project *Project
+	if service != "" {
+		project = findProject(service)
+	}
+
+	remote := override
+	if remote == "" && project != nil {
+		remote = project.Remote
+	}
+	if remote == "" {
+		remote = viper.GetString("remote")
+	}
+	if remote == "" {
+		remote = defaultRemote
+	}
+
+	var mirrors []string
+	if project != nil {
+		mirrors = project.PushRemotes
+	}
+	if len(mirrors) == 0 {
+		mirrors = viper.GetStringSlice("push_remotes")
+	}
+
+	remotes := []string{remote}
+	for _, mirror := range mirrors {
+		if !utils.ContainsString(remotes, mirror) {
+			remotes = append(remotes, mirror)
+		}
+	}
+	return remotes
+}
+
+// checkRemoteSync compares the local head with branch on each remote. The
+// first remote must have the branch; mirrors that do not track it are skipped.
+func checkRemoteSync(git gitRunner, branch string, remotes []string) error {
+	local, err := git("git rev-parse HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get local SHA: %v", err)
+	}
+
+	var unsynced []string
+	for i, remote := range remotes {
+		ref := fmt.Sprintf("%s/%s", remote, branch)
+		sha, err := git(fmt.Sprintf("git rev-parse --verify --quiet %s", ref))
+		switch {
+		case err != nil && i == 0:
+			return fmt.Errorf("remote branch %s not found", ref)
+		case err != nil:
+			fmt.Printf("  ⚠️  %s not found, skipping sync check\n", ref)
+		case sha != local:
+			fmt.Printf("  ❌ %s is at %s, local is at %s\n", ref, shortHash(sha), shortHash(local))
+			unsynced = append(unsynced, remote)
+		default:
+			fmt.Printf("  ✅ %s in sync\n", ref)
+		}
+	}
+
+	if len(unsynced) > 0 {
+		return fmt.Errorf("remote is not synced: %v", unsynced)
+	}
+	return nil
+}
+
+// pushTag pushes a tag to each remote and reports the result per remote. A
+// failed push to the first remote stops there; failed mirror pushes are all
+// reported before the error is returned.
+func pushTag(git gitRunner, tag string, remotes []string) error {
+	var failed []string
+	for i, remote := range remotes {
+		if _, err := git(fmt.Sprintf("git push %s %s", remote, tag)); err != nil {
+			fmt.Printf("  ❌ %s: %v\n", remote, err)
+			if i == 0 {
+				return fmt.Errorf("failed to push %s to %s: %w", tag, remote, err)
+			}
+			failed = append(failed, remote)
+			continue
+		}
+		fmt.Printf("  ✅ %s\n", remote)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s was pushed to %s but not to %v", tag, remotes[0], failed)
+	}
+	return nil
+}
+
+// fetchRemotes refreshes the remote tracking refs and tags before a tag is
+// computed, so the sync check and the next release number are not stale. A
+// mirror that cannot be fetched only produces a warning.
+func fetchRemotes(git gitRunner, remotes []string) error {
+	for i, remote := range remotes {
+		if _, err := git(fmt.Sprintf("git fetch %s --tags", remote)); err != nil {
+			if i == 0 {
+				return fmt.Errorf("failed to fetch %s (use --no-fetch to skip): %v", remote, err)
+			}
+			fmt.Printf("  ⚠️  failed to fetch %s: %v\n", remote, err)
+		}
+	}
+	return nil
+}
+
+// createAndPushTag creates an annotated tag on commit and pushes it to the
+// remotes. When the first remote rejects the tag because another run pushed
+// it meanwhile, the local tag is dropped, the tags are fetched again and
+// recompute is asked for the tag to try next, after a backoff. Mentions of the
+// rejected tag in the comment follow the new tag. It returns the tag that was
+// pushed.
+func createAndPushTag(git gitRunner, tag, comment, commit string, remotes []string, recompute func(taken string) (string, error)) (string, error) {
+	backoff := tagRetryBackoff
+	for attempt := 1; ; attempt++ {
+		if _, err := git(fmt.Sprintf("git tag -a %s -m \"%s\" %s", tag, comment, commit)); err != nil {
+			return tag, fmt.Errorf("failed to create tag %s: %v", tag, err)
+		}
+
+		fmt.Printf("Pushing %s\n", tag)
+		err := pushTag(git, tag, remotes)
+		if err == nil || !isTagRejected(err) {
+			return tag, err
+		}
+		if attempt == maxTagAttempts {
+			return tag, fmt.Errorf("%s still exists on %s after %d attempts", tag, remotes[0], attempt)
+		}
+
+		git(fmt.Sprintf("git tag -d %s", tag))
+		if backoff > 0 {
+			time.Sleep(backoff + time.Duration(rand.Int63n(int64(backoff))))
+			backoff *= 2
+		}
+		if _, err := git(fmt.Sprintf("git fetch %s --tags", remotes[0])); err != nil {
+			return tag, fmt.Errorf("failed to fetch %s: %v", remotes[0], err)
+		}
+
+		next, err := recompute(tag)
+		if err != nil {
+			return tag, fmt.Errorf("%s was taken on %s and the next tag could not be computed: %v", tag, remotes[0], err)
+		}
+		fmt.Printf("⚠️  %s already exists on %s, retrying with %s\n", tag, remotes[0], next)
+
+		comment = strings.ReplaceAll(comment, tag, next)
+		tag = next
+	}
+}
+
+// nextReleaseNumber recomputes a taken tag as the next free release number
+// of the same version, or the next hot fix number
+func nextReleaseNumber(git gitRunner, hotFix bool) func(string) (string, error) {
+	return func(taken string) (string, error) {
+		next := nextFreeTag(git, taken, hotFix)
+		if next == "" {
+			return "", fmt.Errorf("failed to increment tag '%s'", taken)
+		}
+		return next, nil
+	}
+}
+
+// bumpLatestTag recomputes a taken tag by bumping the latest tag of the
+// environment again, which now includes the tags pushed meanwhile
+func bumpLatestTag(git gitRunner, environment, service, dir string, bumpType utils.BumpType) func(string) (string, error) {
+	return func(taken string) (string, error) {
+		latestTag, _, err := utils.GetLatestSemanticVersionInDir(environment, service, dir)
+		if err != nil {
+			return "", err
+		}
+		next, err := utils.BumpTagVersion(latestTag, bumpType, environment, service)
+		if err != nil {
+			return "", err
+		}
+		if next == taken {
+			return nextReleaseNumber(git, false)(taken)
+		}
+		return next, nil
+	}
+}
+
+// lockTagAllocation takes the tag allocation lock of the repository, so that
+// parallel runs on one machine do not compute the same tag
+func lockTagAllocation(git gitRunner) (*utils.FileLock, error) {
+	gitDir, err := git("git rev-parse --path-format=absolute --git-common-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the git directory: %v", err)
+	}
+	return utils.AcquireLock(filepath.Join(gitDir, tagLockFile), tagLockTimeout, tagLockStale)
+}
+
+// lockAndPushTag holds the tag allocation lock while it creates and pushes
+// the tag. A tag that a parallel run on this machine created while the user
+// was answering prompts is recomputed first, so the lock is never held across
+// prompts and is released before the caller exits.
+func lockAndPushTag(git gitRunner, tag, comment, commit string, remotes []string, recompute func(taken string) (string, error)) (string, error) {
+	err := withTagLock(git, func() error {
+		if tagsFetched(git, tag) {
+			next, err := recompute(tag)
+			if err != nil {
+				return fmt.Errorf("%s was created meanwhile and the next tag could not be computed: %v", tag, err)
+			}
+			fmt.Printf("⚠️  %s was created meanwhile, using %s\n", tag, next)
+
+			comment = strings.ReplaceAll(comment, tag, next)
+			tag = next
+		}
+
+		var err error
+		tag, err = createAndPushTag(git, tag, comment, commit, remotes, recompute)
+		return err
+	})
+	return tag, err
+}
+
+// withTagLock runs fn while holding the tag allocation lock, for commands that
+// must check and create more than a tag, such as a release branch, atomically
+func withTagLock(git gitRunner, fn func() error) error {
+	lock, err := lockTagAllocation(git)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	return fn()
+}
+
+// environmentTags lists the tags of an environment, newest first, from the
+// local repository or, when remote is set, from the remote with git ls-remote
+func environmentTags(scope serviceScope, env, service, remote string) ([]string, error) {
+	if remote == "" {
+		return utils.ListEnvironmentTags(env, service, scope.Dir)
+	}
+
+	tags, err := utils.ListRemoteTags(remote, scope.Dir)
+	if err != nil {
+		return nil, err
+	}
+	return utils.FilterEnvironmentTags(utils.RemoteTagNames(tags), env, service), nil
+}
+
+// tagsFetched reports whether all tags exist in the local repository
+func tagsFetched(git gitRunner, tags ...string) bool {
+	for _, tag := range tags {
+		if _, err := git(fmt.Sprintf("git rev-parse --verify --quiet refs/tags/%s", tag)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// nextFreeTag increments a tag until it names a tag that does not exist locally
+func nextFreeTag(git gitRunner, tag string, hotFix bool) string {
+	for {
+		tag = utils.IncrementTag(tag, hotFix)
+		if tag == "" {
+			return ""
+		}
+		if _, err := git(fmt.Sprintf("git rev-parse --verify --quiet refs/tags/%s", tag)); err != nil {
+			return tag
+		}
+	}
+}
+
+// isTagRejected reports whether a push failed because the remote already has the tag
+func isTagRejected(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "already exists")
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestTagRemotes(t *testing.T) {
+	defer viper.Reset()
+
+	if got := tagRemotes("", ""); !reflect.DeepEqual(got, []string{"origin"}) {
+		t.Errorf("tagRemotes() without config = %v, want [origin]", got)
+	}
+
+	viper.Set("remote", "upstream")
+	viper.Set("push_remotes", []string{"deploy"})
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "api", "path": "/tmp/api", "remote": "fork", "push_remotes": []interface{}{"mirror", "fork"}},
+		map[string]interface{}{"name": "web", "path": "/tmp/web"},
+	})
+
+	tests := []struct {
+		service  string
+		override string
+		want     []string
+	}{
+		{"", "", []string{"upstream", "deploy"}},
+		{"web", "", []string{"upstream", "deploy"}},
+		{"api", "", []string{"fork", "mirror"}},
+		{"api", "origin", []string{"origin", "mirror", "fork"}},
+	}
+
+	for _, tt := range tests {
+		if got := tagRemotes(tt.service, tt.override); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tagRemotes(%q, %q) = %v, want %v", tt.service, tt.override, got, tt.want)
+		}
+	}
+}
+
+// initRemotesRepo creates a repository with a pushed main branch on two bare
+// remotes, origin and mirror
+func initRemotesRepo(t *testing.T) (serviceScope, string) {
+	t.Helper()
+	work, _ := initHotfixRepo(t)
+	mirror := filepath.Join(t.TempDir(), "mirror.git")
+	gitIn(t, work, "init", "-q", "--bare", mirror)
+	gitIn(t, work, "remote", "add", "mirror", mirror)
+	gitIn(t, work, "push", "-q", "mirror", "main")
+	return serviceScope{Dir: work}, work
+}
+
+func TestCheckRemoteSync(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+
+	output := captureStdout(t, func() {
+		if err := checkRemoteSync(scope.git, "main", []string{"origin", "mirror", "backup"}); err != nil {
+			t.Errorf("checkRemoteSync unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(output, "origin/main in sync") || !strings.Contains(output, "backup/main not found") {
+		t.Errorf("unexpected sync report:\n%s", output)
+	}
+
+	writeHotfixFile(t, work, "new.txt", "new\n")
+	gitIn(t, work, "add", ".")
+	gitIn(t, work, "commit", "-q", "-m", "feat: unpushed")
+	gitIn(t, work, "push", "-q", "origin", "main")
+
+	var err error
+	output = captureStdout(t, func() {
+		err = checkRemoteSync(scope.git, "main", []string{"origin", "mirror"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "mirror") || strings.Contains(err.Error(), "origin") {
+		t.Errorf("expected only mirror to be out of sync, got %v", err)
+	}
+	if !strings.Contains(output, "❌ mirror/main") {
+		t.Errorf("unexpected sync report:\n%s", output)
+	}
+
+	if err := checkRemoteSync(scope.git, "main", []string{"backup"}); err == nil {
+		t.Error("expected an error when the primary remote does not have the branch")
+	}
+}
+
+func TestPushTag(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+	gitIn(t, work, "tag", "-a", "stg6_1.2.0-1", "-m", "tag")
+
+	var err error
+	output := captureStdout(t, func() {
+		err = pushTag(scope.git, "stg6_1.2.0-1", []string{"origin", "mirror", "missing"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected the missing mirror to fail, got %v", err)
+	}
+	if !strings.Contains(output, "✅ origin") || !strings.Contains(output, "✅ mirror") || !strings.Contains(output, "❌ missing") {
+		t.Errorf("unexpected push report:\n%s", output)
+	}
+	for _, remote := range []string{"origin", "mirror"} {
+		if got := gitIn(t, work, "ls-remote", "--tags", remote, "stg6_1.2.0-1"); got == "" {
+			t.Errorf("tag should be pushed to %s", remote)
+		}
+	}
+
+	if err := pushTag(scope.git, "stg6_1.2.0-1", []string{"missing", "origin"}); err == nil || !strings.Contains(err.Error(), "failed to push") {
+		t.Errorf("expected a failed primary push to stop, got %v", err)
+	}
+}
+
+func TestCreateAndPushTagRetriesTakenTag(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+	defer func(backoff time.Duration) { tagRetryBackoff = backoff }(tagRetryBackoff)
+	tagRetryBackoff = 0
+
+	// A teammate pushes the tag first
+	teammate := filepath.Join(t.TempDir(), "teammate")
+	origin := gitIn(t, work, "remote", "get-url", "origin")
+	gitIn(t, work, "clone", "-q", "-b", "main", origin, teammate)
+	gitIn(t, teammate, "tag", "-a", "stg6_1.2.0-1", "-m", "teammate")
+	gitIn(t, teammate, "push", "-q", "origin", "stg6_1.2.0-1")
+
+	commit := gitIn(t, work, "rev-parse", "HEAD")
+	var tag string
+	var err error
+	output := captureStdout(t, func() {
+		tag, err = createAndPushTag(scope.git, "stg6_1.2.0-1", "stg6_1.2.0-1", commit, []string{"origin"}, nextReleaseNumber(scope.git, false))
+	})
+	if err != nil {
+		t.Fatalf("createAndPushTag unexpected error: %v\n%s", err, output)
+	}
+	if tag != "stg6_1.2.0-2" {
+		t.Errorf("createAndPushTag() = %s, want stg6_1.2.0-2", tag)
+	}
+	if !strings.Contains(output, "retrying with stg6_1.2.0-2") {
+		t.Errorf("retry should be reported:\n%s", output)
+	}
+	if got := gitIn(t, work, "ls-remote", "--tags", "origin", "stg6_1.2.0-2"); got == "" {
+		t.Error("retried tag should be pushed")
+	}
+	if message := gitIn(t, work, "tag", "-l", "--format=%(contents:subject)", "stg6_1.2.0-2"); message != "stg6_1.2.0-2" {
+		t.Errorf("default comment should follow the retried tag, got %q", message)
+	}
+	if message := gitIn(t, work, "tag", "-l", "--format=%(contents:subject)", "stg6_1.2.0-1"); message != "teammate" {
+		t.Errorf("the teammate's tag should replace the local one, got %q", message)
+	}
+}
+
+func TestCreateAndPushTagBumpsAgain(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+	defer func(backoff time.Duration) { tagRetryBackoff = backoff }(tagRetryBackoff)
+	tagRetryBackoff = 0
+
+	gitIn(t, work, "tag", "-a", "stg6_1.2.0-1", "-m", "base")
+	gitIn(t, work, "push", "-q", "origin", "stg6_1.2.0-1")
+
+	// A teammate bumps minor from the same base first
+	teammate := filepath.Join(t.TempDir(), "teammate")
+	origin := gitIn(t, work, "remote", "get-url", "origin")
+	gitIn(t, work, "clone", "-q", "-b", "main", origin, teammate)
+	gitIn(t, teammate, "tag", "-a", "stg6_1.3.0-1", "-m", "teammate")
+	gitIn(t, teammate, "push", "-q", "origin", "stg6_1.3.0-1")
+
+	commit := gitIn(t, work, "rev-parse", "HEAD")
+	recompute := bumpLatestTag(scope.git, "stg6", "", scope.Dir, utils.BumpMinor)
+	var tag string
+	var err error
+	output := captureStdout(t, func() {
+		tag, err = createAndPushTag(scope.git, "stg6_1.3.0-1", "Bump minor version: stg6_1.3.0-1", commit, []string{"origin"}, recompute)
+	})
+	if err != nil {
+		t.Fatalf("createAndPushTag unexpected error: %v\n%s", err, output)
+	}
+	if tag != "stg6_1.4.0-1" {
+		t.Errorf("createAndPushTag() = %s, want stg6_1.4.0-1 bumped from the teammate's tag", tag)
+	}
+	if message := gitIn(t, work, "tag", "-l", "--format=%(contents:subject)", "stg6_1.4.0-1"); message != "Bump minor version: stg6_1.4.0-1" {
+		t.Errorf("comment should name the recomputed tag, got %q", message)
+	}
+}
+
+func TestLockTagAllocation(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+
+	lock, err := lockTagAllocation(scope.git)
+	if err != nil {
+		t.Fatalf("lockTagAllocation unexpected error: %v", err)
+	}
+	if want := filepath.Join(work, ".git", tagLockFile); !strings.HasSuffix(lock.Path, want) {
+		t.Errorf("lock path = %s, want it in the git directory (%s)", lock.Path, want)
+	}
+	lock.Release()
+}
+
+func TestLockAndPushTagSkipsTagCreatedMeanwhile(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+
+	// A parallel run on this machine created the tag while the user was asked
+	gitIn(t, work, "tag", "-a", "stg6_1.2.0-1", "-m", "parallel run")
+
+	commit := gitIn(t, work, "rev-parse", "HEAD")
+	var tag string
+	var err error
+	output := captureStdout(t, func() {
+		tag, err = lockAndPushTag(scope.git, "stg6_1.2.0-1", "stg6_1.2.0-1", commit, []string{"origin"}, nextReleaseNumber(scope.git, false))
+	})
+	if err != nil {
+		t.Fatalf("lockAndPushTag unexpected error: %v\n%s", err, output)
+	}
+	if tag != "stg6_1.2.0-2" {
+		t.Errorf("lockAndPushTag() = %s, want stg6_1.2.0-2", tag)
+	}
+	if message := gitIn(t, work, "tag", "-l", "--format=%(contents:subject)", "stg6_1.2.0-2"); message != "stg6_1.2.0-2" {
+		t.Errorf("default comment should follow the new tag, got %q", message)
+	}
+	if _, err := os.Stat(filepath.Join(work, ".git", tagLockFile)); !os.IsNotExist(err) {
+		t.Error("the tag lock should be released after pushing")
+	}
+}
+
+func TestFetchRemotes(t *testing.T) {
+	scope, _ := initRemotesRepo(t)
+
+	output := captureStdout(t, func() {
+		if err := fetchRemotes(scope.git, []string{"origin", "missing"}); err != nil {
+			t.Errorf("a failing mirror fetch should only warn, got %v", err)
+		}
+	})
+	if !strings.Contains(output, "failed to fetch missing") {
+		t.Errorf("mirror fetch failure should be reported:\n%s", output)
+	}
+
+	if err := fetchRemotes(scope.git, []string{"missing"}); err == nil || !strings.Contains(err.Error(), "--no-fetch") {
+		t.Errorf("expected a failing primary fetch to suggest --no-fetch, got %v", err)
+	}
+}
+
+func TestFetchBeforeTagPolicy(t *testing.T) {
+	defer viper.Reset()
+
+	if !fetchBeforeTagPolicy("") {
+		t.Error("fetching should be enabled by default")
+	}
+
+	viper.Set("fetch_before_tag", false)
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "api", "path": "/tmp/api", "fetch_before_tag": true},
+	})
+	if fetchBeforeTagPolicy("") || fetchBeforeTagPolicy("web") {
+		t.Error("top-level fetch_before_tag: false should disable fetching")
+	}
+	if !fetchBeforeTagPolicy("api") {
+		t.Error("project fetch_before_tag should override the top-level setting")
+	}
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
+	"esh-cli/pkg/config"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -47,6 +50,7 @@ In some projects this triggers deployment in CircleCI.`,
 
 	// Add all subcommands to the new instance
 	addSubcommands(cmd)
+	enableServiceSelection(cmd)
 
 	return cmd
 }
@@ -63,10 +67,17 @@ func addSubcommands(cmd *cobra.Command) {
 	cmd.AddCommand(changelogCmd)
 	cmd.AddCommand(versionDiffCmd)
 	cmd.AddCommand(versionListCmd)
+	cmd.AddCommand(lintCommitsCmd)
+	cmd.AddCommand(hooksCmd)
+	cmd.AddCommand(releaseBranchCmd)
+	cmd.AddCommand(hotfixCmd)
+	cmd.AddCommand(statusCmd)
+	cmd.AddCommand(configCmd)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
+	enableServiceSelection(rootCmd)
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -80,7 +91,56 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.esh-cli.yaml)")
 }
 
-// initConfig reads in config file and ENV variables if set.
+// serviceSelectionAnnotation marks commands whose --service accepts aliases and groups
+const serviceSelectionAnnotation = "esh-cli/service-selection"
+
+// enableServiceSelection makes --service accept project aliases and groups on
+// every command below cmd that has the flag. An alias is replaced with the
+// project name before the command runs, and a group runs the command once per
+// member, stopping at the first member that fails.
+func enableServiceSelection(cmd *cobra.Command) {
+	for _, child := range cmd.Commands() {
+		enableServiceSelection(child)
+	}
+
+	flag := cmd.Flags().Lookup("service")
+	if flag == nil || cmd.Run == nil || cmd.Annotations[serviceSelectionAnnotation] != "" {
+		return
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[serviceSelectionAnnotation] = "true"
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		selected := flag.Value.String()
+		services := selectServices(selected)
+		for i, service := range services {
+			if err := flag.Value.Set(service); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(services) > 1 {
+				fmt.Fprintf(os.Stderr, "\n━━ %s (%s %d/%d) ━━\n", service, selected, i+1, len(services))
+			}
+			run(c, args)
+		}
+	}
+}
+
+// configSource is a config file the configuration was read from
+type configSource struct {
+	Kind string
+	Path string
+}
+
+// configSources are the config files read, lowest precedence first: the user
+// config, then repository configs
+var configSources []configSource
+
+// initConfig reads in config file and ENV variables if set. A repository
+// config found from the working directory is merged over the user config.
 func initConfig() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -94,16 +154,102 @@ func initConfig() {
 	}
 
 	viper.AutomaticEnv()
+	configSources = nil
 
-	if err := viper.ReadInConfig(); err == nil {
+	err := viper.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	switch {
+	case err == nil:
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
-	} else {
-		// Config file not found - check if we should auto-initialize
-		// Only auto-initialize if this is not the init command itself
-		if shouldAutoInitialize() {
-			fmt.Fprintln(os.Stderr, "🤖 No configuration found. Consider running 'esh-cli init' for AI project discovery.")
+		configSources = append(configSources, configSource{Kind: "user", Path: viper.ConfigFileUsed()})
+		warnOutdatedConfig(viper.ConfigFileUsed())
+	case errors.As(err, &notFound) || errors.Is(err, os.ErrNotExist):
+	default:
+		exitOnBrokenConfig(viper.ConfigFileUsed(), err, "")
+	}
+
+	if dir, err := os.Getwd(); err == nil {
+		mergeRepoConfig(dir)
+	}
+
+	// Config file not found - check if we should auto-initialize
+	// Only auto-initialize if this is not the init command itself
+	if len(configSources) == 0 && shouldAutoInitialize() {
+		fmt.Fprintln(os.Stderr, "🤖 No configuration found. Consider running 'esh-cli init' for AI project discovery.")
+	}
+}
+
+// mergeRepoConfig merges the repository config found from dir over the
+// configuration read so far, so team conventions kept in a repository win
+// over the user config. Environment variables still win over both.
+func mergeRepoConfig(dir string) {
+	path := findRepoConfig(dir)
+	if path == "" {
+		return
+	}
+	for _, source := range configSources {
+		if source.Path == path {
+			return
 		}
 	}
+
+	raw, err := config.ReadFile(path)
+	if err != nil {
+		exitOnBrokenConfig(path, err, " --repo")
+		return
+	}
+	if err := viper.MergeConfigMap(raw); err != nil {
+		exitOnBrokenConfig(path, err, " --repo")
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Using repository config file:", path)
+	configSources = append(configSources, configSource{Kind: "repo", Path: path})
+	warnOutdatedConfig(path)
+}
+
+// findRepoConfig returns the repository config file of dir or its parents,
+// never the user config file
+func findRepoConfig(dir string) string {
+	skip := []string{getConfigFilePath()}
+	if home, err := os.UserHomeDir(); err == nil {
+		skip = append(skip, filepath.Join(home, config.FileName))
+	}
+	return config.FindRepoFile(dir, skip...)
+}
+
+// exitOnBrokenConfig reports a config file that cannot be read and exits. The
+// config commands report and fix a broken file themselves.
+func exitOnBrokenConfig(path string, err error, flag string) {
+	if isConfigCommand() {
+		return
+	}
+	if _, parseErr := config.ReadFile(path); parseErr != nil {
+		err = parseErr
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	fmt.Fprintf(os.Stderr, "Run 'esh-cli config edit%s' to fix it.\n", flag)
+	os.Exit(1)
+}
+
+// warnOutdatedConfig points to config migrate when a config file is older than
+// this esh-cli. Old files still work, they are migrated in memory when validated.
+func warnOutdatedConfig(path string) {
+	if isConfigCommand() || !shouldAutoInitialize() {
+		return
+	}
+	raw, err := config.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if fileVersion, err := config.FileVersion(raw); err == nil && fileVersion < config.CurrentVersion {
+		fmt.Fprintf(os.Stderr, "⚠️  %s is config_version %d, run 'esh-cli config migrate' to upgrade it.\n", path, fileVersion)
+	}
+}
+
+// isConfigCommand reports whether the config command is running
+func isConfigCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == "config"
 }
 
 // shouldAutoInitialize checks if we should show auto-initialization message
@@ -114,6 +260,10 @@ func shouldAutoInitialize() bool {
 		if command == "init" || command == "help" || command == "--help" || command == "-h" {
 			return false
 		}
+		// Hook scripts run on every commit and checkout, keep them quiet
+		if command == "hooks" && len(os.Args) > 2 && os.Args[2] == "run" {
+			return false
+		}
 	}
 	return true
 }
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// serviceScope is the repository directory and path globs a command's git
+// history is limited to. The zero value is the current directory without
+// path restrictions.
+type serviceScope struct {
+	Name  string
+	Dir   string
+	Paths []string
+}
+
+// resolveServiceScope looks up the directory and monorepo paths of a service from the config
+func resolveServiceScope(service string) (serviceScope, error) {
+	if service == "" {
+		return serviceScope{}, nil
+	}
+
+	// Make sure config is loaded when service is specified
+	initConfig()
+
+	path := findProjectPath(service)
+	if path == "" {
+		return serviceScope{}, fmt.Errorf("service '%s' not found in configuration", service)
+	}
+
+	// The service's repository may keep its own conventions
+	mergeRepoConfig(path)
+
+	return serviceScope{
+		Name:  service,
+		Dir:   path,
+		Paths: findProjectPaths(service),
+	}, nil
+}
+
+// git runs a git command in the scope directory
+func (s serviceScope) git(command string) (string, error) {
+	if s.Dir == "" {
+		return utils.Cmd(command)
+	}
+	return utils.CmdInDir(command, s.Dir)
+}
+
+// pathspec returns the pathspec arguments restricting a git command to the scope paths
+func (s serviceScope) pathspec() string {
+	return utils.PathspecArgs(s.Paths)
+}
+
+// selectServices resolves a --service value to the services a command runs
+// for. A project alias selects the project by its name, so tags keep the
+// project's prefix, and a group selects its members. Other values are
+// returned unchanged, so commands report unknown services themselves.
+func selectServices(service string) []string {
+	if service == "" {
+		return []string{""}
+	}
+	if project := findProject(service); project != nil {
+		return []string{project.Name}
+	}
+
+	for group, members := range viper.GetStringMapStringSlice("groups") {
+		if !strings.EqualFold(group, service) {
+			continue
+		}
+		var services []string
+		for _, member := range members {
+			name := member
+			if project := findProject(member); project != nil {
+				name = project.Name
+			}
+			if !utils.ContainsString(services, name) {
+				services = append(services, name)
+			}
+		}
+		return services
+	}
+
+	return []string{service}
+}
+
+// configuredServiceNames returns the names of all projects in the configuration
+func configuredServiceNames() []string {
+	var names []string
+	for _, project := range loadedProjects() {
+		if project.Name != "" {
+			names = append(names, project.Name)
+		}
+	}
+
+	return names
+}
+
+// initialDevelopmentPolicy reports whether the 0.x versions of a service follow
+// the initial development policy. A project's initial_development setting
+// overrides the top-level one.
+func initialDevelopmentPolicy(service string) bool {
+	if service != "" {
+		if project := findProject(service); project != nil {
+			if project.InitialDevelopment != nil {
+				return *project.InitialDevelopment
+			}
+		}
+	}
+	return viper.GetBool("initial_development")
+}
+
+// initialVersionSetting returns the configured version of a service's first
+// tag. A project's initial_version setting overrides the top-level one.
+func initialVersionSetting(service string) string {
+	if service != "" {
+		if project := findProject(service); project != nil {
+			if project.InitialVersion != "" {
+				return project.InitialVersion
+			}
+		}
+	}
+	return viper.GetString("initial_version")
+}
+
+// fetchBeforeTagPolicy reports whether remotes are fetched before a tag is
+// computed. A project's fetch_before_tag setting overrides the top-level one;
+// both default to true.
+func fetchBeforeTagPolicy(service string) bool {
+	if service != "" {
+		if project := findProject(service); project != nil {
+			if project.FetchBeforeTag != nil {
+				return *project.FetchBeforeTag
+			}
+		}
+	}
+	if viper.IsSet("fetch_before_tag") {
+		return viper.GetBool("fetch_before_tag")
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func TestResolveServiceScope(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{
+			"name":  "api",
+			"path":  "/repo",
+			"paths": []interface{}{"services/api/**", "libs/shared/**"},
+		},
+		map[string]interface{}{
+			"name": "worker",
+			"path": "/worker",
+		},
+	})
+
+	tests := []struct {
+		name      string
+		service   string
+		want      serviceScope
+		shouldErr bool
+	}{
+		{"no service", "", serviceScope{}, false},
+		{"monorepo service", "api", serviceScope{Name: "api", Dir: "/repo", Paths: []string{"services/api/**", "libs/shared/**"}}, false},
+		{"service without paths", "worker", serviceScope{Name: "worker", Dir: "/worker"}, false},
+		{"unknown service", "missing", serviceScope{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveServiceScope(tt.service)
+			if tt.shouldErr {
+				if err == nil {
+					t.Errorf("resolveServiceScope(%q) expected error, got nil", tt.service)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveServiceScope(%q) unexpected error: %v", tt.service, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveServiceScope(%q) = %+v, want %+v", tt.service, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceScopePathspec(t *testing.T) {
+	if got := (serviceScope{}).pathspec(); got != "" {
+		t.Errorf("Expected empty pathspec without paths, got %q", got)
+	}
+
+	scope := serviceScope{Paths: []string{"services/api/**"}}
+	if got := scope.pathspec(); got != " -- ':(glob)services/api/**'" {
+		t.Errorf("Unexpected pathspec: %q", got)
+	}
+}
+
+func TestInitialDevelopmentPolicy(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("initial_development", true)
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "api", "path": "/repo", "initial_development": false},
+		map[string]interface{}{"name": "worker", "path": "/worker"},
+	})
+
+	tests := []struct {
+		service string
+		want    bool
+	}{
+		{"", true},        // top-level setting
+		{"worker", true},  // inherits top-level setting
+		{"api", false},    // project overrides top-level setting
+		{"missing", true}, // unknown projects use the top-level setting
+	}
+
+	for _, tt := range tests {
+		if got := initialDevelopmentPolicy(tt.service); got != tt.want {
+			t.Errorf("initialDevelopmentPolicy(%q) = %t, want %t", tt.service, got, tt.want)
+		}
+	}
+
+	viper.Reset()
+	if initialDevelopmentPolicy("") {
+		t.Error("Initial development policy should be disabled by default")
+	}
+}
+
+func TestSelectServices(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "payments-api", "path": "/api", "aliases": []interface{}{"api"}},
+		map[string]interface{}{"name": "payments-worker", "path": "/worker", "aliases": []interface{}{"worker"}},
+		map[string]interface{}{"name": "ledger", "path": "/ledger"},
+	})
+	viper.Set("groups", map[string]interface{}{
+		"payments": []interface{}{"api", "worker", "ledger", "payments-api"},
+	})
+
+	tests := []struct {
+		service string
+		want    []string
+	}{
+		{"", []string{""}},
+		{"ledger", []string{"ledger"}},
+		{"API", []string{"payments-api"}},
+		{"payments", []string{"payments-api", "payments-worker", "ledger"}},
+		{"missing", []string{"missing"}},
+	}
+	for _, tt := range tests {
+		if got := selectServices(tt.service); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("selectServices(%q) = %v, want %v", tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestEnableServiceSelection(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("projects", []interface{}{
+		map[string]interface{}{"name": "payments-api", "path": "/api", "aliases": []interface{}{"api"}},
+		map[string]interface{}{"name": "ledger", "path": "/ledger"},
+	})
+	viper.Set("groups", map[string]interface{}{"payments": []interface{}{"api", "ledger"}})
+
+	var service string
+	var ran []string
+	root := &cobra.Command{Use: "root"}
+	child := &cobra.Command{
+		Use: "child",
+		Run: func(cmd *cobra.Command, args []string) { ran = append(ran, service) },
+	}
+	child.Flags().StringVarP(&service, "service", "s", "", "")
+	root.AddCommand(child)
+
+	enableServiceSelection(root)
+	// Enabling twice must not run members twice
+	enableServiceSelection(root)
+
+	for _, tt := range []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"child", "-s", "payments"}, []string{"payments-api", "ledger"}},
+		{[]string{"child", "--service", "api"}, []string{"payments-api"}},
+	} {
+		ran = nil
+		root.SetArgs(tt.args)
+		if err := root.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ran, tt.want) {
+			t.Errorf("%v ran for %v, want %v", tt.args, ran, tt.want)
+		}
+	}
+}
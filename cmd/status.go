@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusService string
+	statusRemote  string
+)
+
+// envTagStatus is the latest local and remote tag of an environment
+type envTagStatus struct {
+	Environment string
+	Local       string
+	Remote      string
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [environment]",
+	Short: "Compare local tags with the tags on the remote",
+	Long: `Compare the local tags with the tags on the remote, read with git ls-remote
+so nothing is fetched.
+
+For each environment the latest local and remote tags are shown, followed by
+the tags that differ:
+- local only: never pushed, or deleted upstream
+- remote only: pushed by someone else and not fetched yet
+- changed: the tag points to a different object on the remote`,
+	Example: `  esh-cli status                     # All environments against the configured remote
+  esh-cli status production2         # A single environment
+  esh-cli status --service api       # The api service's tags
+  esh-cli status --remote upstream   # Against another remote`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVarP(&statusService, "service", "s", "", "Service name for tagging")
+	statusCmd.Flags().StringVar(&statusRemote, "remote", "", "Remote to compare with (default: the configured remote)")
+}
+
+func runStatus(cmd *cobra.Command, args []string) {
+	environments := utils.ENVS
+	if len(args) == 1 {
+		if !utils.ContainsString(utils.ENVS, args[0]) {
+			fmt.Fprintf(os.Stderr, "Error: invalid environment '%s'. Valid environments: %v\n",
+				args[0], utils.ENVS)
+			os.Exit(1)
+		}
+		environments = []string{args[0]}
+	}
+
+	scope, err := resolveServiceScope(statusService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	remote := tagRemotes(statusService, statusRemote)[0]
+	remoteTags, err := utils.ListRemoteTags(remote, scope.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	local, err := utils.ListLocalTagObjects(scope.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	statuses, keep := environmentTagStatus(environments, statusService, local, remoteTags)
+	diff := utils.CompareTagSets(local, remoteTags, keep)
+
+	fmt.Printf("📡 Tag Status: local vs %s\n\n", remote)
+	fmt.Printf("%-12s %-28s %-28s %s\n", "ENV", "LOCAL", "REMOTE", "STATE")
+	for _, status := range statuses {
+		fmt.Printf("%-12s %-28s %-28s %s\n", status.Environment, orNone(status.Local), orNone(status.Remote), status.state())
+	}
+
+	if diff.IsEmpty() {
+		fmt.Printf("\n✅ Local tags match %s\n", remote)
+		return
+	}
+
+	printTagList(fmt.Sprintf("⬆️  Not on %s (never pushed or deleted upstream)", remote), diff.LocalOnly,
+		fmt.Sprintf("push with 'git push %s <tag>', or drop a tag deleted upstream with 'git tag -d <tag>'", remote))
+	printTagList(fmt.Sprintf("⬇️  Only on %s (not fetched)", remote), diff.RemoteOnly,
+		fmt.Sprintf("fetch with 'git fetch %s --tags'", remote))
+	printTagList(fmt.Sprintf("⚠️  Different on %s", remote), diff.Changed,
+		fmt.Sprintf("the tag was moved; replace the local one with 'git fetch %s --tags --force'", remote))
+}
+
+// environmentTagStatus finds the latest local and remote tag of each
+// environment, and returns a filter accepting the environment tags compared
+func environmentTagStatus(environments []string, service string, local map[string]string, remoteTags []utils.RemoteTag) ([]envTagStatus, func(string) bool) {
+	localNames := make([]string, 0, len(local))
+	for name := range local {
+		localNames = append(localNames, name)
+	}
+	remoteNames := utils.RemoteTagNames(remoteTags)
+
+	compared := map[string]bool{}
+	var statuses []envTagStatus
+	for _, env := range environments {
+		localTags := utils.FilterEnvironmentTags(localNames, env, service)
+		remoteEnvTags := utils.FilterEnvironmentTags(remoteNames, env, service)
+
+		status := envTagStatus{Environment: env}
+		if len(localTags) > 0 {
+			status.Local = localTags[0]
+		}
+		if len(remoteEnvTags) > 0 {
+			status.Remote = remoteEnvTags[0]
+		}
+		statuses = append(statuses, status)
+
+		for _, tag := range append(localTags, remoteEnvTags...) {
+			compared[tag] = true
+		}
+	}
+
+	return statuses, func(tag string) bool { return compared[tag] }
+}
+
+// state describes how the latest local tag relates to the latest remote tag
+func (s envTagStatus) state() string {
+	switch {
+	case s.Local == "" && s.Remote == "":
+		return "-"
+	case s.Local == s.Remote:
+		return "✅ in sync"
+	case s.Local == "":
+		return "⬇️  behind"
+	case s.Remote == "":
+		return "⬆️  ahead"
+	}
+
+	if cmp, err := utils.CompareTags(s.Local, s.Remote); err == nil && cmp > 0 {
+		return "⬆️  ahead"
+	}
+	return "⬇️  behind"
+}
+
+// printTagList prints a titled list of tags with a hint, if there are any
+func printTagList(title string, tags []string, hint string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", title)
+	for _, tag := range tags {
+		fmt.Printf("  • %s\n", tag)
+	}
+	fmt.Printf("  💡 %s\n", hint)
+}
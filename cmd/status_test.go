@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"esh-cli/pkg/utils"
+	"reflect"
+	"testing"
+)
+
+func TestEnvironmentTagStatus(t *testing.T) {
+	local := map[string]string{
+		"dev_1.3.0-1":  "a",
+		"stg6_1.2.0-2": "b",
+		"stg6_1.2.0-1": "c",
+		"notes":        "d",
+	}
+	remoteTags := []utils.RemoteTag{
+		{Name: "dev_1.3.0-1", Object: "a"},
+		{Name: "stg6_1.2.0-1", Object: "c"},
+		{Name: "demo_1.1.0-1", Object: "e"},
+	}
+
+	statuses, keep := environmentTagStatus([]string{"dev", "stg6", "demo", "production2"}, "", local, remoteTags)
+	want := []envTagStatus{
+		{Environment: "dev", Local: "dev_1.3.0-1", Remote: "dev_1.3.0-1"},
+		{Environment: "stg6", Local: "stg6_1.2.0-2", Remote: "stg6_1.2.0-1"},
+		{Environment: "demo", Remote: "demo_1.1.0-1"},
+		{Environment: "production2"},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("environmentTagStatus() = %+v, want %+v", statuses, want)
+	}
+
+	states := []string{"✅ in sync", "⬆️  ahead", "⬇️  behind", "-"}
+	for i, status := range statuses {
+		if got := status.state(); got != states[i] {
+			t.Errorf("%s state() = %q, want %q", status.Environment, got, states[i])
+		}
+	}
+
+	if keep("notes") || !keep("stg6_1.2.0-2") || !keep("demo_1.1.0-1") {
+		t.Error("only environment tags should be compared")
+	}
+}
+
+func TestEnvironmentTagsFromRemote(t *testing.T) {
+	scope, work := initRemotesRepo(t)
+	gitIn(t, work, "tag", "-a", "production2_1.3.0-1", "-m", "local only")
+	gitIn(t, work, "push", "-q", "origin", "production2_1.2.0-1")
+
+	local, err := environmentTags(scope, "production2", "", "")
+	if err != nil || !reflect.DeepEqual(local, []string{"production2_1.3.0-1", "production2_1.2.0-1"}) {
+		t.Errorf("environmentTags() local = %v (%v)", local, err)
+	}
+
+	remote, err := environmentTags(scope, "production2", "", "origin")
+	if err != nil || !reflect.DeepEqual(remote, []string{"production2_1.2.0-1"}) {
+		t.Errorf("environmentTags() from origin = %v (%v), want only the pushed tag", remote, err)
+	}
+
+	if !tagsFetched(scope.git, "production2_1.3.0-1") || tagsFetched(scope.git, "production2_9.9.9-1") {
+		t.Error("tagsFetched should report whether the tags exist locally")
+	}
+}
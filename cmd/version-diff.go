@@ -4,6 +4,7 @@ import (
 	"esh-cli/pkg/utils"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,6 +18,8 @@ var (
 	diffShowFiles   bool
 	diffShowStats   bool
 	diffSinceDate   string
+	diffService     string
+	diffSort        string
 )
 
 // versionDiffCmd represents the version-diff command
@@ -32,11 +35,18 @@ This command helps understand the changes between versions, including:
 - Release timeline analysis
 
 If only one tag is provided, it compares with the previous version.
-If no tags are provided, it analyzes the current environment.`,
+If no tags are provided, it analyzes the current environment.
+
+With --remote, tags are read from the remote with git ls-remote, so the history
+and previous versions reflect what was pushed even without an up-to-date clone.
+Commits, files and statistics still need the tags fetched locally.`,
 	Example: `  esh-cli version-diff stg6_1.2.3-1 stg6_1.2.4-1    # Compare two specific tags
   esh-cli version-diff stg6_1.2.3-1 --commits         # Show commits since this tag
   esh-cli version-diff stg6 --history                 # Show version history for environment
-  esh-cli version-diff --since 2024-01-01             # Show changes since date`,
+  esh-cli version-diff production2 --remote           # Show the history pushed to the remote
+  esh-cli version-diff production2 --sort tag-date    # Show the history in the order it was tagged
+  esh-cli version-diff --since 2024-01-01             # Show changes since date
+  esh-cli version-diff api_stg6_1.2.4-1 --service api --files  # Only the api service paths`,
 	Args: cobra.MinimumNArgs(1),
 	Run:  runVersionDiff,
 }
@@ -45,14 +55,34 @@ func init() {
 	rootCmd.AddCommand(versionDiffCmd)
 
 	versionDiffCmd.Flags().BoolVar(&diffShowHistory, "history", false, "Show version history")
-	versionDiffCmd.Flags().BoolVar(&diffShowRemote, "remote", false, "Compare with remote tags")
+	versionDiffCmd.Flags().BoolVar(&diffShowRemote, "remote", false, "Read tags from the remote with git ls-remote")
 	versionDiffCmd.Flags().BoolVar(&diffShowCommits, "commits", false, "Show commits between versions")
 	versionDiffCmd.Flags().BoolVar(&diffShowFiles, "files", false, "Show changed files")
 	versionDiffCmd.Flags().BoolVar(&diffShowStats, "stats", false, "Show detailed statistics")
 	versionDiffCmd.Flags().StringVar(&diffSinceDate, "since", "", "Show changes since date (YYYY-MM-DD)")
+	versionDiffCmd.Flags().StringVar(&diffSort, "sort", sortByVersion, "Sort the history by version, tag-date or commit-date")
+	versionDiffCmd.Flags().StringVarP(&diffService, "service", "s", "", "Limit the comparison to a service's repository and paths")
 }
 
 func runVersionDiff(cmd *cobra.Command, args []string) {
+	scope, err := resolveServiceScope(diffService)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !utils.ContainsString(sortOrders, diffSort) {
+		fmt.Fprintf(os.Stderr, "Error: invalid sort order '%s'. Valid orders: %v\n", diffSort, sortOrders)
+		os.Exit(1)
+	}
+
+	// Read tags from the service's remote instead of the local repository
+	remote := ""
+	if diffShowRemote {
+		remote = tagRemotes(diffService, "")[0]
+		fmt.Printf("Reading tags from %s\n\n", remote)
+	}
+
 	if len(args) == 1 && !utils.IsTagValid(args[0]) {
 		// First argument is environment, show environment history
 		environment := args[0]
@@ -61,7 +91,7 @@ func runVersionDiff(cmd *cobra.Command, args []string) {
 				environment, utils.ENVS)
 			os.Exit(1)
 		}
-		showEnvironmentHistory(environment)
+		showEnvironmentHistory(environment, scope, remote)
 		return
 	}
 
@@ -72,8 +102,7 @@ func runVersionDiff(cmd *cobra.Command, args []string) {
 		tag2 = args[1]
 	} else {
 		// Find previous tag automatically
-		var err error
-		tag2, err = findPreviousTag(tag1)
+		tag2, err = findPreviousTag(tag1, scope, remote)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error finding previous tag: %v\n", err)
 			os.Exit(1)
@@ -91,35 +120,38 @@ func runVersionDiff(cmd *cobra.Command, args []string) {
 	}
 
 	// Compare versions
-	err := compareVersions(tag1, tag2)
+	err = compareVersions(tag1, tag2, scope)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error comparing versions: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func showEnvironmentHistory(environment string) {
+func showEnvironmentHistory(environment string, scope serviceScope, remote string) {
 	fmt.Printf("📊 Version History for Environment: %s\n\n", environment)
 
 	// Get all tags for environment
-	pattern := fmt.Sprintf("*_%s_*", environment)
-	output, err := utils.Cmd(fmt.Sprintf("git tag -l '%s' --sort=-version:refname", pattern))
+	tags, err := environmentTags(scope, environment, scope.Name, remote)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error listing tags: %v\n", err)
 		os.Exit(1)
 	}
 
-	if output == "" {
+	if len(tags) == 0 {
 		fmt.Printf("No tags found for environment '%s'\n", environment)
 		return
 	}
 
-	tags := strings.Split(output, "\n")
-	if len(tags) == 0 {
-		fmt.Printf("No tags found for environment '%s'\n", environment)
-		return
+	// Tag dates come from the metadata of all local tags, read at once; remote
+	// tags that were not fetched have no date
+	metadata, err := utils.LoadTagMetadata(scope.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading tags: %v\n", err)
+		os.Exit(1)
 	}
 
+	sortTagsByDate(tags, metadata, diffSort)
+
 	fmt.Printf("Found %d versions:\n\n", len(tags))
 
 	for i, tag := range tags {
@@ -133,15 +165,14 @@ func showEnvironmentHistory(environment string) {
 			continue
 		}
 
-		// Get tag date
-		dateStr, err := utils.Cmd(fmt.Sprintf("git log -1 --format=%%ai %s", tag))
-		if err != nil {
-			dateStr = "unknown"
-		}
-
-		var date time.Time
-		if dateStr != "unknown" {
-			date, _ = time.Parse("2006-01-02 15:04:05 -0700", dateStr)
+		// A tag made long after its commit (a promotion) shows both dates
+		entry := metadata[tag]
+		dateStr := ""
+		if !entry.TagDate.IsZero() {
+			dateStr = " - " + entry.TagDate.Format("2006-01-02")
+			if commitDay := entry.CommitDate.Format("2006-01-02"); !entry.CommitDate.IsZero() && commitDay != entry.TagDate.Format("2006-01-02") {
+				dateStr += fmt.Sprintf(" (committed %s)", commitDay)
+			}
 		}
 
 		// Show semantic version difference from previous
@@ -154,11 +185,7 @@ func showEnvironmentHistory(environment string) {
 			}
 		}
 
-		if date.IsZero() {
-			fmt.Printf("  %s (%s)%s\n", tag, version, diffStr)
-		} else {
-			fmt.Printf("  %s (%s) - %s%s\n", tag, version, date.Format("2006-01-02"), diffStr)
-		}
+		fmt.Printf("  %s (%s)%s%s\n", tag, version, dateStr, diffStr)
 	}
 
 	if diffShowStats {
@@ -166,7 +193,24 @@ func showEnvironmentHistory(environment string) {
 	}
 }
 
-func compareVersions(tag1, tag2 string) error {
+// sortTagsByDate orders tags newest first by their tag or commit date; the
+// version order is kept for the version sort and for tags without metadata
+func sortTagsByDate(tags []string, metadata map[string]utils.TagMetadata, order string) {
+	date := func(tag string) time.Time { return metadata[tag].TagDate }
+	switch order {
+	case sortByDate, sortByTagDate:
+	case sortByCommitDate:
+		date = func(tag string) time.Time { return metadata[tag].CommitDate }
+	default:
+		return
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return date(tags[i]).After(date(tags[j]))
+	})
+}
+
+func compareVersions(tag1, tag2 string, scope serviceScope) error {
 	if tag2 == "" {
 		fmt.Printf("📋 Analyzing Version: %s\n\n", tag1)
 	} else {
@@ -193,11 +237,17 @@ func compareVersions(tag1, tag2 string) error {
 		fmt.Printf("Version: %s\n", version1)
 	}
 
+	// Commits, files and stats need the tag objects, which --remote does not fetch
+	if !tagsFetched(scope.git, tag1) || (tag2 != "" && !tagsFetched(scope.git, tag2)) {
+		fmt.Printf("\n💡 Fetch the tags (git fetch --tags) to see commits, files and statistics\n")
+		return nil
+	}
+
 	// Show commits if requested or if no tag2
 	if diffShowCommits || tag2 == "" {
 		if tag2 != "" {
 			fmt.Printf("\n📝 Commits between %s and %s:\n", tag2, tag1)
-			commits, err := utils.GetCommitsBetweenTags(tag2, tag1)
+			commits, err := utils.GetCommitsBetweenTagsInDir(tag2, tag1, scope.Dir, scope.Paths)
 			if err != nil {
 				return fmt.Errorf("error getting commits: %v", err)
 			}
@@ -205,7 +255,7 @@ func compareVersions(tag1, tag2 string) error {
 		} else {
 			fmt.Printf("\n📝 Recent commits up to %s:\n", tag1)
 			// Show last 10 commits up to tag
-			output, err := utils.Cmd(fmt.Sprintf("git log --oneline -10 %s", tag1))
+			output, err := scope.git(fmt.Sprintf("git log --oneline -10 %s%s", tag1, scope.pathspec()))
 			if err != nil {
 				return fmt.Errorf("error getting commits: %v", err)
 			}
@@ -223,7 +273,7 @@ func compareVersions(tag1, tag2 string) error {
 	// Show files if requested
 	if diffShowFiles && tag2 != "" {
 		fmt.Printf("\n📁 Changed Files:\n")
-		output, err := utils.Cmd(fmt.Sprintf("git diff --name-only %s..%s", tag2, tag1))
+		output, err := scope.git(fmt.Sprintf("git diff --name-only %s..%s%s", tag2, tag1, scope.pathspec()))
 		if err != nil {
 			return fmt.Errorf("error getting changed files: %v", err)
 		}
@@ -242,46 +292,39 @@ func compareVersions(tag1, tag2 string) error {
 	// Show stats if requested
 	if diffShowStats && tag2 != "" {
 		fmt.Printf("\n📊 Statistics:\n")
-		showDiffStats(tag2, tag1)
+		showDiffStats(tag2, tag1, scope)
 	}
 
 	return nil
 }
 
-func findPreviousTag(tag string) (string, error) {
-	// Extract environment from tag
-	parts := strings.Split(tag, "_")
-	if len(parts) < 2 {
+func findPreviousTag(tag string, scope serviceScope, remote string) (string, error) {
+	info, err := utils.ParseTag(tag)
+	if err != nil {
 		return "", fmt.Errorf("invalid tag format")
 	}
 
-	var env string
-	if len(parts) == 3 {
-		// service_env_version format
-		env = parts[1]
-	} else {
-		// env_version format
-		env = parts[0]
-	}
-
 	// Get all tags for environment, sorted by version
-	pattern := fmt.Sprintf("*_%s_*", env)
-	output, err := utils.Cmd(fmt.Sprintf("git tag -l '%s' --sort=-version:refname", pattern))
+	tags, err := environmentTags(scope, info.Environment, info.Service, remote)
 	if err != nil {
 		return "", err
 	}
 
-	if output == "" {
+	if len(tags) == 0 {
 		return "", fmt.Errorf("no tags found for environment")
 	}
 
-	tags := strings.Split(output, "\n")
-
-	// Find current tag and return the next one
+	// Find current tag and return the next one with the same service prefix
 	for i, t := range tags {
-		if t == tag && i+1 < len(tags) {
-			return tags[i+1], nil
+		if t != tag {
+			continue
+		}
+		for _, previous := range tags[i+1:] {
+			if previousInfo, err := utils.ParseTag(previous); err == nil && previousInfo.Service == info.Service {
+				return previous, nil
+			}
 		}
+		break
 	}
 
 	return "", fmt.Errorf("previous tag not found")
@@ -319,21 +362,21 @@ func showCommits(commits []string) {
 	}
 }
 
-func showDiffStats(tag1, tag2 string) {
+func showDiffStats(tag1, tag2 string, scope serviceScope) {
 	// Get commit count
-	output, err := utils.Cmd(fmt.Sprintf("git rev-list --count %s..%s", tag1, tag2))
+	output, err := scope.git(fmt.Sprintf("git rev-list --count %s..%s%s", tag1, tag2, scope.pathspec()))
 	if err == nil && output != "" {
 		fmt.Printf("  Commits: %s\n", strings.TrimSpace(output))
 	}
 
 	// Get file changes
-	output, err = utils.Cmd(fmt.Sprintf("git diff --shortstat %s..%s", tag1, tag2))
+	output, err = scope.git(fmt.Sprintf("git diff --shortstat %s..%s%s", tag1, tag2, scope.pathspec()))
 	if err == nil && output != "" {
 		fmt.Printf("  Changes: %s\n", strings.TrimSpace(output))
 	}
 
 	// Get contributors
-	output, err = utils.Cmd(fmt.Sprintf("git shortlog -sn %s..%s", tag1, tag2))
+	output, err = scope.git(fmt.Sprintf("git shortlog -sn %s..%s%s", tag1, tag2, scope.pathspec()))
 	if err == nil && output != "" {
 		lines := strings.Split(output, "\n")
 		fmt.Printf("  Contributors: %d\n", len(lines))
@@ -19,6 +19,7 @@ var (
 	listFormat string
 	listSort   string
 	listLimit  int
+	listRemote bool
 )
 
 // versionListCmd represents the version-list command
@@ -28,13 +29,18 @@ var versionListCmd = &cobra.Command{
 	Long: `List semantic versions for environments with advanced filtering and formatting options.
 
 This command provides enhanced listing capabilities for tags with semantic version awareness,
-including filtering by version components, cross-environment comparison, and multiple output formats.`,
+including filtering by version components, cross-environment comparison, and multiple output formats.
+
+With --remote, tags are read from the remote with git ls-remote instead of the local
+repository. Dates and messages are only known for tags that were fetched.`,
 	Example: `  esh-cli version-list stg6                    # List all versions for stg6
   esh-cli version-list stg6 --major 1         # Filter by major version 1
   esh-cli version-list stg6 --major 1 --minor 2  # Filter by version 1.2.x
   esh-cli version-list --all                  # Compare all environments
   esh-cli version-list stg6 --format json     # Output as JSON
-  esh-cli version-list stg6 --sort date       # Sort by date instead of version`,
+  esh-cli version-list stg6 --sort date       # Sort by tag date instead of version
+  esh-cli version-list stg6 --sort commit-date # Sort by the date of the tagged commits
+  esh-cli version-list --all --remote         # What is pushed, without fetching`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runVersionList,
 }
@@ -46,10 +52,24 @@ func init() {
 	versionListCmd.Flags().IntVar(&listMajor, "major", -1, "filter by major version")
 	versionListCmd.Flags().IntVar(&listMinor, "minor", -1, "filter by minor version")
 	versionListCmd.Flags().StringVar(&listFormat, "format", "table", "output format (table, json, compact)")
-	versionListCmd.Flags().StringVar(&listSort, "sort", "version", "sort order (version, date)")
+	versionListCmd.Flags().StringVar(&listSort, "sort", "version", "sort order (version, tag-date, commit-date; date is tag-date)")
 	versionListCmd.Flags().IntVar(&listLimit, "limit", 10, "maximum number of results per environment")
+	versionListCmd.Flags().BoolVar(&listRemote, "remote", false, "read tags from the remote with git ls-remote")
 }
 
+// Sort orders of version-list and version-diff --history; date is the tag date
+const (
+	sortByVersion    = "version"
+	sortByDate       = "date"
+	sortByTagDate    = "tag-date"
+	sortByCommitDate = "commit-date"
+)
+
+var sortOrders = []string{sortByVersion, sortByDate, sortByTagDate, sortByCommitDate}
+
+// VersionInfo is a tag with its version components and metadata. Date is
+// when the tag was created (the commit date for lightweight tags), CommitDate
+// when the tagged commit was; a promotion tags an old commit with a new date.
 type VersionInfo struct {
 	Tag         string    `json:"tag"`
 	Environment string    `json:"environment"`
@@ -60,6 +80,7 @@ type VersionInfo struct {
 	Patch       int       `json:"patch"`
 	Release     string    `json:"release"`
 	Date        time.Time `json:"date"`
+	CommitDate  time.Time `json:"commit_date"`
 	Commit      string    `json:"commit"`
 	Message     string    `json:"message"`
 }
@@ -83,16 +104,38 @@ func runVersionList(cmd *cobra.Command, args []string) {
 		environments = []string{environment}
 	}
 
+	if !utils.ContainsString(sortOrders, listSort) {
+		fmt.Fprintf(os.Stderr, "Error: invalid sort order '%s'. Valid orders: %v\n", listSort, sortOrders)
+		os.Exit(1)
+	}
+
+	// Read the metadata of all local tags at once, from the cache where possible
+	metadata, err := utils.LoadTagMetadata("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Read the remote's tags once for all environments
+	var remoteTags []utils.RemoteTag
+	if listRemote {
+		remote := tagRemotes("", "")[0]
+		remoteTags, err = utils.ListRemoteTags(remote, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Collect version information
 	var allVersions []VersionInfo
 
 	for _, env := range environments {
-		versions, err := getVersionsForEnvironment(env)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: error getting versions for %s: %v\n", env, err)
-			continue
+		if listRemote {
+			allVersions = append(allVersions, getRemoteVersionsForEnvironment(env, remoteTags, metadata)...)
+		} else {
+			allVersions = append(allVersions, getVersionsForEnvironment(env, metadata)...)
 		}
-		allVersions = append(allVersions, versions...)
 	}
 
 	// Apply filters
@@ -119,39 +162,70 @@ func runVersionList(cmd *cobra.Command, args []string) {
 	}
 }
 
-func getVersionsForEnvironment(env string) ([]VersionInfo, error) {
-	// Get all tags for environment
-	pattern := fmt.Sprintf("*_%s_*", env)
-	output, err := utils.Cmd(fmt.Sprintf("git tag -l '%s' --sort=-version:refname", pattern))
-	if err != nil {
-		return nil, fmt.Errorf("error listing tags: %v", err)
+// getVersionsForEnvironment builds the versions of an environment from the local tags
+func getVersionsForEnvironment(env string, metadata map[string]utils.TagMetadata) []VersionInfo {
+	tags := make([]string, 0, len(metadata))
+	for tag := range metadata {
+		tags = append(tags, tag)
 	}
 
-	if output == "" {
-		return []VersionInfo{}, nil
+	var versions []VersionInfo
+	for _, tag := range utils.FilterEnvironmentTags(tags, env, "") {
+		versionInfo, err := parseVersionInfo(tag, env, metadata)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping invalid tag %s: %v\n", tag, err)
+			continue
+		}
+
+		versions = append(versions, versionInfo)
 	}
 
-	tags := strings.Split(output, "\n")
-	var versions []VersionInfo
+	return versions
+}
 
-	for _, tag := range tags {
-		if tag == "" {
+// getRemoteVersionsForEnvironment builds the versions of an environment from
+// tags listed on a remote, with local metadata for the tags that were fetched
+func getRemoteVersionsForEnvironment(env string, remoteTags []utils.RemoteTag, metadata map[string]utils.TagMetadata) []VersionInfo {
+	var versions []VersionInfo
+	for _, tag := range remoteTags {
+		if info, err := utils.ParseTag(tag.Name); err != nil || info.Environment != env {
 			continue
 		}
 
-		versionInfo, err := parseVersionInfo(tag, env)
+		versionInfo, err := parseVersionInfo(tag.Name, env, metadata)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: skipping invalid tag %s: %v\n", tag, err)
+			fmt.Fprintf(os.Stderr, "Warning: skipping invalid tag %s: %v\n", tag.Name, err)
 			continue
 		}
 
+		versionInfo.Commit = tag.Commit
 		versions = append(versions, versionInfo)
 	}
 
-	return versions, nil
+	return versions
 }
 
-func parseVersionInfo(tag, env string) (VersionInfo, error) {
+// parseVersionInfo parses a tag and adds its commit, date and message from the
+// tag metadata; tags without metadata have an unknown commit and date
+func parseVersionInfo(tag, env string, metadata map[string]utils.TagMetadata) (VersionInfo, error) {
+	versionInfo, err := parseTagVersionInfo(tag, env)
+	if err != nil {
+		return versionInfo, err
+	}
+
+	versionInfo.Commit = "unknown"
+	if entry, ok := metadata[tag]; ok {
+		versionInfo.Commit = entry.Commit
+		versionInfo.Date = entry.TagDate
+		versionInfo.CommitDate = entry.CommitDate
+		versionInfo.Message = entry.Message
+	}
+	return versionInfo, nil
+}
+
+// parseTagVersionInfo parses the version components of a tag without reading
+// any metadata from the repository
+func parseTagVersionInfo(tag, env string) (VersionInfo, error) {
 	if !utils.IsTagValid(tag) {
 		return VersionInfo{}, fmt.Errorf("invalid tag format")
 	}
@@ -193,29 +267,6 @@ func parseVersionInfo(tag, env string) (VersionInfo, error) {
 		return VersionInfo{}, fmt.Errorf("error parsing semantic version: %v", err)
 	}
 
-	// Get tag metadata
-	commit, err := utils.Cmd(fmt.Sprintf("git rev-list -n 1 %s", tag))
-	if err != nil {
-		commit = "unknown"
-	}
-
-	// Get tag date
-	dateStr, err := utils.Cmd(fmt.Sprintf("git log -1 --format=%%ai %s", tag))
-	if err != nil {
-		dateStr = ""
-	}
-
-	var tagDate time.Time
-	if dateStr != "" {
-		tagDate, _ = time.Parse("2006-01-02 15:04:05 -0700", dateStr)
-	}
-
-	// Get tag message
-	message, err := utils.Cmd(fmt.Sprintf("git tag -l --format='%%(contents)' %s", tag))
-	if err != nil {
-		message = ""
-	}
-
 	return VersionInfo{
 		Tag:         tag,
 		Environment: env,
@@ -225,9 +276,6 @@ func parseVersionInfo(tag, env string) (VersionInfo, error) {
 		Minor:       sv.Minor,
 		Patch:       sv.Patch,
 		Release:     release,
-		Date:        tagDate,
-		Commit:      commit,
-		Message:     strings.TrimSpace(message),
 	}, nil
 }
 
@@ -252,11 +300,16 @@ func applyFilters(versions []VersionInfo) []VersionInfo {
 }
 
 func sortVersions(versions []VersionInfo) {
-	if listSort == "date" {
-		sort.Slice(versions, func(i, j int) bool {
+	switch listSort {
+	case sortByDate, sortByTagDate:
+		sort.SliceStable(versions, func(i, j int) bool {
 			return versions[i].Date.After(versions[j].Date)
 		})
-	} else {
+	case sortByCommitDate:
+		sort.SliceStable(versions, func(i, j int) bool {
+			return versions[i].CommitDate.After(versions[j].CommitDate)
+		})
+	default:
 		// Sort by semantic version (descending)
 		sort.Slice(versions, func(i, j int) bool {
 			vi := versions[i]
@@ -294,22 +347,18 @@ func outputTable(versions []VersionInfo) {
 
 	for env, envVersions := range envGroups {
 		fmt.Printf("\n🏷️  Environment: %s\n", env)
-		fmt.Printf("%-25s %-12s %-8s %-20s %-12s\n", "Tag", "Version", "Release", "Date", "Commit")
-		fmt.Println(strings.Repeat("-", 85))
+		fmt.Printf("%-25s %-12s %-8s %-18s %-18s %-12s\n", "Tag", "Version", "Release", "Tagged", "Committed", "Commit")
+		fmt.Println(strings.Repeat("-", 98))
 
 		for _, v := range envVersions {
-			dateStr := v.Date.Format("2006-01-02 15:04")
-			if v.Date.IsZero() {
-				dateStr = "unknown"
-			}
-
 			commitShort := v.Commit
 			if len(commitShort) > 8 {
 				commitShort = commitShort[:8]
 			}
 
-			fmt.Printf("%-25s %-12s %-8s %-20s %-12s\n",
-				v.Tag, v.Version, v.Release, dateStr, commitShort)
+			fmt.Printf("%-25s %-12s %-8s %-18s %-18s %-12s\n",
+				v.Tag, v.Version, v.Release, formatDate(v.Date, "2006-01-02 15:04"),
+				formatDate(v.CommitDate, "2006-01-02 15:04"), commitShort)
 		}
 	}
 }
@@ -333,6 +382,8 @@ func outputJSON(versions []VersionInfo) {
 		fmt.Printf("    \"minor\": %d,\n", v.Minor)
 		fmt.Printf("    \"patch\": %d,\n", v.Patch)
 		fmt.Printf("    \"release\": \"%s\",\n", v.Release)
+		fmt.Printf("    \"date\": \"%s\",\n", jsonDate(v.Date))
+		fmt.Printf("    \"commit_date\": \"%s\",\n", jsonDate(v.CommitDate))
 		fmt.Printf("    \"commit\": \"%s\",\n", v.Commit)
 		fmt.Printf("    \"message\": \"%s\"\n", v.Message)
 		if i < len(versions)-1 {
@@ -343,3 +394,19 @@ func outputJSON(versions []VersionInfo) {
 	}
 	fmt.Println("]")
 }
+
+// formatDate formats a date with the layout, or returns "unknown" for a zero date
+func formatDate(date time.Time, layout string) string {
+	if date.IsZero() {
+		return "unknown"
+	}
+	return date.Format(layout)
+}
+
+// jsonDate formats a date for the JSON output, empty for a zero date
+func jsonDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.RFC3339)
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"bytes"
 	"esh-cli/pkg/utils"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestVersionDiffCmdCreation(t *testing.T) {
@@ -23,7 +25,7 @@ func TestVersionDiffCmdCreation(t *testing.T) {
 
 func TestVersionDiffFlags(t *testing.T) {
 	// Test that flags exist
-	flags := []string{"history", "remote", "commits", "files", "stats", "since"}
+	flags := []string{"history", "remote", "commits", "files", "stats", "since", "service", "sort"}
 
 	for _, flagName := range flags {
 		flag := versionDiffCmd.Flags().Lookup(flagName)
@@ -298,3 +300,32 @@ func TestVersionDiffFromToLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestSortTagsByDate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	metadata := map[string]utils.TagMetadata{
+		// Promoted late: an old commit tagged last
+		"stg6_1.0.0-1": {TagDate: day(20), CommitDate: day(1)},
+		"stg6_1.1.0-1": {TagDate: day(10), CommitDate: day(5)},
+		"stg6_1.2.0-1": {TagDate: day(15), CommitDate: day(12)},
+	}
+	versionOrder := []string{"stg6_1.2.0-1", "stg6_1.1.0-1", "stg6_1.0.0-1"}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{sortByVersion, versionOrder},
+		{sortByTagDate, []string{"stg6_1.0.0-1", "stg6_1.2.0-1", "stg6_1.1.0-1"}},
+		{sortByDate, []string{"stg6_1.0.0-1", "stg6_1.2.0-1", "stg6_1.1.0-1"}},
+		{sortByCommitDate, versionOrder},
+	}
+
+	for _, tt := range tests {
+		tags := append([]string{}, versionOrder...)
+		sortTagsByDate(tags, metadata, tt.order)
+		if !reflect.DeepEqual(tags, tt.want) {
+			t.Errorf("sortTagsByDate(%s) = %v, want %v", tt.order, tags, tt.want)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"esh-cli/pkg/utils"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestVersionListCmdCreation(t *testing.T) {
@@ -23,7 +27,7 @@ func TestVersionListCmdCreation(t *testing.T) {
 
 func TestVersionListFlags(t *testing.T) {
 	// Test that flags exist
-	flags := []string{"all", "major", "minor", "format", "sort", "limit"}
+	flags := []string{"all", "major", "minor", "format", "sort", "limit", "remote"}
 
 	for _, flagName := range flags {
 		flag := versionListCmd.Flags().Lookup(flagName)
@@ -215,3 +219,78 @@ func TestVersionListServiceLogic(t *testing.T) {
 		})
 	}
 }
+
+func TestSortVersionsByDate(t *testing.T) {
+	defer func(order string) { listSort = order }(listSort)
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	versions := []VersionInfo{
+		{Tag: "stg6_1.2.0-1", Major: 1, Minor: 2, Release: "1", Date: day(15), CommitDate: day(12)},
+		{Tag: "stg6_1.0.0-1", Major: 1, Minor: 0, Release: "1", Date: day(20), CommitDate: day(1)},
+		{Tag: "stg6_1.1.0-1", Major: 1, Minor: 1, Release: "1", Date: day(10), CommitDate: day(5)},
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{sortByVersion, []string{"stg6_1.2.0-1", "stg6_1.1.0-1", "stg6_1.0.0-1"}},
+		{sortByTagDate, []string{"stg6_1.0.0-1", "stg6_1.2.0-1", "stg6_1.1.0-1"}},
+		{sortByCommitDate, []string{"stg6_1.2.0-1", "stg6_1.1.0-1", "stg6_1.0.0-1"}},
+	}
+
+	for _, tt := range tests {
+		listSort = tt.order
+		sorted := append([]VersionInfo{}, versions...)
+		sortVersions(sorted)
+
+		var tags []string
+		for _, v := range sorted {
+			tags = append(tags, v.Tag)
+		}
+		if strings.Join(tags, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("sortVersions(%s) = %v, want %v", tt.order, tags, tt.want)
+		}
+	}
+}
+
+func TestVersionListJSONKeys(t *testing.T) {
+	want := []string{"commit", "commit_date", "date", "environment", "major", "message", "minor", "patch", "release", "service", "tag", "version"}
+
+	date := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+	info := VersionInfo{Tag: "stg6_1.2.0-1", Date: date, CommitDate: date.AddDate(0, 0, -1)}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var marshaled map[string]interface{}
+	if err := json.Unmarshal(data, &marshaled); err != nil {
+		t.Fatal(err)
+	}
+	if got := sortedKeys(marshaled); !reflect.DeepEqual(got, want) {
+		t.Errorf("VersionInfo JSON keys = %v, want %v", got, want)
+	}
+
+	output := captureStdout(t, func() { outputJSON([]VersionInfo{info}) })
+	var printed []map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &printed); err != nil {
+		t.Fatalf("--format json output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(printed) != 1 || !reflect.DeepEqual(sortedKeys(printed[0]), want) {
+		t.Errorf("--format json keys = %v, want %v", printed, want)
+	}
+	if printed[0]["date"] != "2025-03-20T00:00:00Z" || printed[0]["commit_date"] != "2025-03-19T00:00:00Z" {
+		t.Errorf("unexpected dates in %v", printed[0])
+	}
+}
+
+// sortedKeys returns the keys of a JSON object in order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,210 @@
+// Package config defines the esh-cli configuration file: its schema, its
+// validation and the migration of files written by older versions.
+package config
+
+import (
+	"errors"
+	"esh-cli/pkg/utils"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config_version of files written by this esh-cli.
+// Files without config_version were written before it existed and are version 1.
+const CurrentVersion = 2
+
+// Config is the esh-cli configuration file
+type Config struct {
+	ConfigVersion      int                 `mapstructure:"config_version" yaml:"config_version"`
+	Projects           []Project           `mapstructure:"projects" yaml:"projects,omitempty"`
+	Groups             map[string][]string `mapstructure:"groups" yaml:"groups,omitempty"`
+	Remote             string              `mapstructure:"remote" yaml:"remote,omitempty"`
+	PushRemotes        []string            `mapstructure:"push_remotes" yaml:"push_remotes,omitempty"`
+	FetchBeforeTag     *bool               `mapstructure:"fetch_before_tag" yaml:"fetch_before_tag,omitempty"`
+	InitialDevelopment bool                `mapstructure:"initial_development" yaml:"initial_development,omitempty"`
+	InitialVersion     string              `mapstructure:"initial_version" yaml:"initial_version,omitempty"`
+	Branches           []utils.BranchRule  `mapstructure:"branches" yaml:"branches,omitempty"`
+	Changelog          Changelog           `mapstructure:"changelog" yaml:"changelog,omitempty"`
+	Lint               utils.LintRules     `mapstructure:"lint" yaml:"lint,omitempty"`
+
+	// Written by esh-cli init
+	InitializedAt  string `mapstructure:"initialized_at" yaml:"initialized_at,omitempty"`
+	Version        string `mapstructure:"version" yaml:"version,omitempty"`
+	AutoDiscovered bool   `mapstructure:"auto_discovered" yaml:"auto_discovered,omitempty"`
+}
+
+// Project is a service esh-cli tags. Its settings override the top-level ones.
+type Project struct {
+	Name               string   `json:"name" mapstructure:"name" yaml:"name"`
+	Path               string   `json:"path" mapstructure:"path" yaml:"path"`
+	Aliases            []string `json:"aliases,omitempty" mapstructure:"aliases" yaml:"aliases,omitempty"`
+	Type               string   `json:"type" mapstructure:"type" yaml:"type"`
+	Paths              []string `json:"paths,omitempty" mapstructure:"paths" yaml:"paths,omitempty"`
+	InitialDevelopment *bool    `json:"initial_development,omitempty" mapstructure:"initial_development" yaml:"initial_development,omitempty"`
+	InitialVersion     string   `json:"initial_version,omitempty" mapstructure:"initial_version" yaml:"initial_version,omitempty"`
+	Remote             string   `json:"remote,omitempty" mapstructure:"remote" yaml:"remote,omitempty"`
+	PushRemotes        []string `json:"push_remotes,omitempty" mapstructure:"push_remotes" yaml:"push_remotes,omitempty"`
+	FetchBeforeTag     *bool    `json:"fetch_before_tag,omitempty" mapstructure:"fetch_before_tag" yaml:"fetch_before_tag,omitempty"`
+
+	// RemoteURL identifies the repository when its directory moves
+	RemoteURL string `json:"remote_url,omitempty" mapstructure:"remote_url" yaml:"remote_url,omitempty"`
+}
+
+// Changelog is the commit taxonomy used by changelog, bump-version --auto and lint-commits
+type Changelog struct {
+	Types   []utils.CommitType `mapstructure:"types" yaml:"types,omitempty"`
+	Exclude []string           `mapstructure:"exclude" yaml:"exclude,omitempty"`
+}
+
+// invalidKeysPattern matches the mapstructure error for keys the schema does not know
+var invalidKeysPattern = regexp.MustCompile(`^'(.*)' has invalid keys: (.*)$`)
+
+// ReadFile reads a config file into a raw map, reporting YAML errors with
+// their line number
+func ReadFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, path)
+}
+
+// Parse parses config YAML into a raw map; name identifies the source in errors
+func Parse(data []byte, name string) (map[string]interface{}, error) {
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s is not valid YAML: %s", name, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return raw, nil
+}
+
+// WriteFile writes a raw config map as YAML
+func WriteFile(path string, raw map[string]interface{}) error {
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// Decode decodes a raw config map, already migrated to CurrentVersion, into a
+// Config. Unknown keys and values of the wrong type are each reported.
+func Decode(raw map[string]interface{}) (*Config, []error) {
+	cfg := &Config{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused: true,
+		Result:      cfg,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	if err := decoder.Decode(raw); err != nil {
+		return cfg, decodeErrors(err)
+	}
+	return cfg, nil
+}
+
+// DecodeProject decodes one raw project entry. Values of the wrong type are
+// left empty, so a broken entry still matches by the fields that decode.
+func DecodeProject(raw interface{}) Project {
+	var project Project
+	mapstructure.Decode(raw, &project)
+	return project
+}
+
+// Load reads, migrates in memory, decodes and validates a config file. All
+// problems are returned; the config is nil when the file cannot be parsed.
+func Load(path string) (*Config, []error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return LoadData(data, path)
+}
+
+// LoadData is Load for config YAML that is not read from a file yet
+func LoadData(data []byte, name string) (*Config, []error) {
+	raw, err := Parse(data, name)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if _, err := Migrate(raw); err != nil {
+		return nil, []error{err}
+	}
+
+	cfg, errs := Decode(raw)
+	if len(errs) > 0 {
+		return cfg, errs
+	}
+	return cfg, cfg.Validate()
+}
+
+// FindProject returns the project with a name or alias, compared case-insensitively
+func (c *Config) FindProject(name string) *Project {
+	for i := range c.Projects {
+		if c.Projects[i].Matches(name) {
+			return &c.Projects[i]
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a name is the project's name or one of its aliases,
+// compared case-insensitively
+func (p Project) Matches(name string) bool {
+	if strings.EqualFold(p.Name, name) {
+		return true
+	}
+	for _, alias := range p.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeErrors splits a mapstructure error into one error per problem
+func decodeErrors(err error) []error {
+	var errs []error
+	for _, problem := range flattenErrors(err) {
+		message := problem.Error()
+		if matches := invalidKeysPattern.FindStringSubmatch(message); matches != nil {
+			if matches[1] == "" {
+				message = fmt.Sprintf("unknown key: %s", matches[2])
+			} else {
+				message = fmt.Sprintf("%s: unknown key: %s", matches[1], matches[2])
+			}
+		}
+		errs = append(errs, errors.New(message))
+	}
+	return errs
+}
+
+// flattenErrors unwraps joined errors into their leaves
+func flattenErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, inner := range joined.Unwrap() {
+			errs = append(errs, flattenErrors(inner)...)
+		}
+		return errs
+	}
+	if inner := errors.Unwrap(err); inner != nil {
+		if _, ok := inner.(interface{ Unwrap() []error }); ok {
+			return flattenErrors(inner)
+		}
+	}
+	return []error{err}
+}
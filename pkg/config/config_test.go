@@ -0,0 +1,242 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReportsLine(t *testing.T) {
+	_, err := Parse([]byte("remote: origin\nprojects:\n  - name: api\n    path: /src/api\nlint: [\n"), ".esh-cli.yaml")
+	if err == nil {
+		t.Fatal("Parse() should fail on bad indentation")
+	}
+	if !strings.Contains(err.Error(), ".esh-cli.yaml is not valid YAML: line 5") {
+		t.Errorf("Parse() error = %q, want the file and line", err)
+	}
+}
+
+func TestDecodeReportsEachProblem(t *testing.T) {
+	raw, err := Parse([]byte(`
+config_version: 2
+remot: origin
+projects:
+  - name: api
+    path: /src/api
+    pahts: ["api/**"]
+lint:
+  max_header_length: long
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, errs := Decode(raw)
+	messages := joinErrors(errs)
+	for _, want := range []string{"unknown key: remot", "projects[0]: unknown key: pahts", "lint.max_header_length"} {
+		if !strings.Contains(messages, want) {
+			t.Errorf("Decode() errors %q should mention %q", messages, want)
+		}
+	}
+}
+
+func TestDecodeProject(t *testing.T) {
+	project := DecodeProject(map[string]interface{}{
+		"name":    "api",
+		"aliases": []interface{}{"a"},
+		"paths":   "not a list",
+		"unknown": true,
+	})
+	if project.Name != "api" || !project.Matches("A") {
+		t.Errorf("DecodeProject() = %+v, want api with alias a", project)
+	}
+	if project.Paths != nil {
+		t.Errorf("a value of the wrong type should be left empty, got %v", project.Paths)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{
+			name: "valid",
+			yaml: "config_version: 2\nremote: origin\nprojects:\n  - name: api\n    path: /src/api\n    initial_version: 0.1.0\n",
+		},
+		{
+			name: "projects",
+			yaml: "projects:\n  - path: /src/a\n  - name: api\n  - name: API\n    path: /src/b\n    push_remotes: ['']\n",
+			want: []string{"projects[0]: name is required", "projects[1] (api): path is required", "projects[2] (API): duplicate project name", "invalid remote name"},
+		},
+		{
+			name: "aliases and groups",
+			yaml: "projects:\n  - name: api\n    path: /a\n    aliases: [pay, web]\n  - name: web\n    path: /w\n    aliases: ['a.b']\ngroups:\n  pay: [api]\n  all: [api, ledger]\n",
+			want: []string{"projects[1] (web): duplicate project name", "invalid alias \"a.b\"", "groups.pay: the group name is already", "groups.all: unknown project \"ledger\""},
+		},
+		{
+			name: "newer version",
+			yaml: "config_version: 9\n",
+			want: []string{"config_version 9 is newer"},
+		},
+		{
+			name: "branches and changelog",
+			yaml: "branches:\n  - pattern: 'x/*'\nchangelog:\n  types:\n    - name: ''\n",
+			want: []string{"branches: branch rule \"x/*\": type is required", "changelog:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := LoadData([]byte(tt.yaml), "test")
+			if len(tt.want) == 0 && len(errs) > 0 {
+				t.Fatalf("LoadData() errors = %v, want none", errs)
+			}
+			messages := joinErrors(errs)
+			for _, want := range tt.want {
+				if !strings.Contains(messages, want) {
+					t.Errorf("errors %q should mention %q", messages, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	raw, err := Parse([]byte("projects: []\nproject_paths: [/src/api]\nsearch_patterns: [pocketful]\n"), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := Migrate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || !strings.HasPrefix(applied[0], "1 → 2") {
+		t.Errorf("Migrate() applied %v, want the 1 → 2 step", applied)
+	}
+	want := map[string]interface{}{"projects": []interface{}{}, "config_version": CurrentVersion}
+	if !reflect.DeepEqual(raw, want) {
+		t.Errorf("Migrate() = %v, want %v", raw, want)
+	}
+
+	if applied, _ := Migrate(raw); len(applied) != 0 {
+		t.Errorf("Migrate() of a current config applied %v", applied)
+	}
+	if _, err := Migrate(map[string]interface{}{"config_version": "two"}); err == nil {
+		t.Error("Migrate() should reject a config_version that is not a number")
+	}
+}
+
+func TestGetAndSetValue(t *testing.T) {
+	raw, err := Parse([]byte("remote: origin\nprojects:\n  - name: api\n    path: /src/api\n"), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := GetValue(raw, "projects.API.path"); !ok || value != "/src/api" {
+		t.Errorf("GetValue(projects.API.path) = %v, %v", value, ok)
+	}
+	if value, ok := GetValue(raw, "projects.0.name"); !ok || value != "api" {
+		t.Errorf("GetValue(projects.0.name) = %v, %v", value, ok)
+	}
+	if _, ok := GetValue(raw, "projects.web.path"); ok {
+		t.Error("GetValue() should not find a missing project")
+	}
+
+	if err := SetValue(raw, "projects.api.push_remotes", ParseValue("[origin, mirror]")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(raw, "lint.max_header_length", ParseValue("72")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue(raw, "projects.web.path", "/src/web"); err == nil {
+		t.Error("SetValue() should not create list items")
+	}
+
+	cfg, errs := Decode(raw)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if got := cfg.FindProject("api").PushRemotes; !reflect.DeepEqual(got, []string{"origin", "mirror"}) {
+		t.Errorf("push_remotes = %v", got)
+	}
+	if cfg.Lint.MaxHeaderLength != 72 {
+		t.Errorf("lint.max_header_length = %d, want 72", cfg.Lint.MaxHeaderLength)
+	}
+}
+
+func joinErrors(errs []error) string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+func TestFindRepoFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "repo", "services", "api")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := filepath.Join(root, FileName)
+	repo := filepath.Join(root, "repo", FileName)
+	for _, path := range []string{user, repo} {
+		if err := os.WriteFile(path, []byte("remote: origin\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := FindRepoFile(nested, user); got != repo {
+		t.Errorf("FindRepoFile() = %q, want the closest file %q", got, repo)
+	}
+	if err := os.Remove(repo); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindRepoFile(nested, user); got != "" {
+		t.Errorf("FindRepoFile() = %q, the user config is not a repository config", got)
+	}
+	if got := FindRepoFile(nested); got != user {
+		t.Errorf("FindRepoFile() without skip = %q, want %q", got, user)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	raw, err := Parse([]byte(`
+remote: origin
+push_remotes: [a, b]
+lint:
+  max_header_length: 72
+projects:
+  - name: api
+    path: /src/api
+branches:
+  - pattern: "x/*"
+    type: feature
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"remote":                 "origin",
+		"push_remotes":           []interface{}{"a", "b"},
+		"lint.max_header_length": 72,
+		"projects.api.name":      "api",
+		"projects.api.path":      "/src/api",
+		"branches.0.pattern":     "x/*",
+		"branches.0.type":        "feature",
+	}
+	if got := Flatten(raw); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten() = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got, ok := GetValue(raw, key); !ok || !reflect.DeepEqual(got, value) {
+			t.Errorf("GetValue(%s) = %v, want the flattened value %v", key, got, value)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetValue returns the value at a dotted key of a raw config. A key segment
+// selects a list item by index or, for lists of projects, by name, so
+// projects.api.remote is the remote of the api project.
+func GetValue(raw map[string]interface{}, key string) (interface{}, bool) {
+	var current interface{} = raw
+	for _, segment := range strings.Split(key, ".") {
+		next, ok := child(current, segment)
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// SetValue sets the value at a dotted key of a raw config, creating missing
+// maps along the way. List items must already exist.
+func SetValue(raw map[string]interface{}, key string, value interface{}) error {
+	segments := strings.Split(key, ".")
+	var current interface{} = raw
+	for i, segment := range segments[:len(segments)-1] {
+		next, ok := child(current, segment)
+		if !ok {
+			parent, isMap := current.(map[string]interface{})
+			if !isMap {
+				return fmt.Errorf("%s: no item %q", strings.Join(segments[:i], "."), segment)
+			}
+			next = map[string]interface{}{}
+			parent[segment] = next
+		}
+		current = next
+	}
+
+	last := segments[len(segments)-1]
+	switch parent := current.(type) {
+	case map[string]interface{}:
+		parent[last] = value
+	case []interface{}:
+		index, ok := listIndex(parent, last)
+		if !ok {
+			return fmt.Errorf("%s: no item %q", strings.Join(segments[:len(segments)-1], "."), last)
+		}
+		parent[index] = value
+	default:
+		return fmt.Errorf("%s is not a map or list", strings.Join(segments[:len(segments)-1], "."))
+	}
+	return nil
+}
+
+// ParseValue parses a value given on the command line as YAML, so true, 3 and
+// [a, b] become a bool, a number and a list. Anything else stays a string.
+func ParseValue(text string) interface{} {
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(text), &value); err != nil || value == nil {
+		return text
+	}
+	return value
+}
+
+// child returns a map value or a list item selected by a key segment
+func child(current interface{}, segment string) (interface{}, bool) {
+	switch node := current.(type) {
+	case map[string]interface{}:
+		value, ok := node[segment]
+		return value, ok
+	case []interface{}:
+		index, ok := listIndex(node, segment)
+		if !ok {
+			return nil, false
+		}
+		return node[index], true
+	}
+	return nil, false
+}
+
+// listIndex finds a list item by index or by the name of a map item
+func listIndex(list []interface{}, segment string) (int, bool) {
+	if index, err := strconv.Atoi(segment); err == nil {
+		return index, index >= 0 && index < len(list)
+	}
+	for i, item := range list {
+		if named, ok := item.(map[string]interface{}); ok {
+			if name, ok := named["name"].(string); ok && strings.EqualFold(name, segment) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Flatten returns the leaf values of a raw config by dotted key, with the keys
+// GetValue accepts. Items of lists of maps are keyed by name when they all
+// have one, such as projects, and by index otherwise. Lists of scalars are
+// leaves.
+func Flatten(raw map[string]interface{}) map[string]interface{} {
+	leaves := map[string]interface{}{}
+	flattenInto(leaves, "", raw)
+	return leaves
+}
+
+// flattenInto adds the leaves below a value to leaves
+func flattenInto(leaves map[string]interface{}, prefix string, value interface{}) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch node := value.(type) {
+	case map[string]interface{}:
+		if len(node) == 0 && prefix != "" {
+			leaves[prefix] = node
+		}
+		for key, child := range node {
+			flattenInto(leaves, join(key), child)
+		}
+	case []interface{}:
+		keys, ok := itemKeys(node)
+		if !ok {
+			leaves[prefix] = node
+			return
+		}
+		for i, item := range node {
+			flattenInto(leaves, join(keys[i]), item)
+		}
+	default:
+		leaves[prefix] = value
+	}
+}
+
+// itemKeys returns the key segments of the items of a non-empty list of maps
+func itemKeys(list []interface{}) ([]string, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+
+	keys := make([]string, len(list))
+	named := true
+	for i, item := range list {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := itemMap["name"].(string)
+		if !ok || name == "" {
+			named = false
+		}
+		keys[i] = name
+	}
+	if !named {
+		for i := range keys {
+			keys[i] = strconv.Itoa(i)
+		}
+	}
+	return keys, true
+}
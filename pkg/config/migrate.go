@@ -0,0 +1,59 @@
+package config
+
+import "fmt"
+
+// migration upgrades a raw config from one version to the next
+type migration struct {
+	from        int
+	description string
+	apply       func(raw map[string]interface{})
+}
+
+// migrations upgrade configs step by step, oldest first
+var migrations = []migration{
+	{
+		from:        1,
+		description: "remove project_paths and search_patterns, which init wrote but nothing reads",
+		apply: func(raw map[string]interface{}) {
+			delete(raw, "project_paths")
+			delete(raw, "search_patterns")
+		},
+	},
+}
+
+// FileVersion returns the config_version of a raw config, 1 when it has none
+func FileVersion(raw map[string]interface{}) (int, error) {
+	value, ok := raw["config_version"]
+	if !ok {
+		return 1, nil
+	}
+	version, ok := value.(int)
+	if !ok || version < 1 {
+		return 0, fmt.Errorf("config_version must be a positive number, got %v", value)
+	}
+	return version, nil
+}
+
+// Migrate upgrades a raw config to CurrentVersion in place and describes each
+// step applied. Configs from a newer esh-cli are left alone; Validate reports them.
+func Migrate(raw map[string]interface{}) ([]string, error) {
+	version, err := FileVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	for _, step := range migrations {
+		if step.from < version {
+			continue
+		}
+		step.apply(raw)
+		applied = append(applied, fmt.Sprintf("%d → %d: %s", step.from, step.from+1, step.description))
+		version = step.from + 1
+	}
+
+	if version >= CurrentVersion {
+		raw["config_version"] = version
+	}
+	return applied, nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileName is the name of the user config file in the home directory and of
+// repository config files
+const FileName = ".esh-cli.yaml"
+
+// FindRepoFile looks for a repository config file in dir and its parents and
+// returns the closest one, or an empty string. User config files are passed
+// as skip, so walking up through the home directory does not take them for a
+// repository config.
+func FindRepoFile(dir string, skip ...string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	skipped := map[string]bool{}
+	for _, path := range skip {
+		if abs, err := filepath.Abs(path); err == nil {
+			skipped[abs] = true
+		}
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && !skipped[path] {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
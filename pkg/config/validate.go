@@ -0,0 +1,114 @@
+package config
+
+import (
+	"esh-cli/pkg/utils"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Validate checks the values the schema cannot express: required and unique
+// project names and aliases, group members, remote names, versions, branch
+// rules, commit types and lint rules. It returns one error per problem.
+func (c *Config) Validate() []error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.ConfigVersion > CurrentVersion {
+		add("config_version %d is newer than this esh-cli supports (%d), upgrade esh-cli", c.ConfigVersion, CurrentVersion)
+	}
+
+	// Project names and aliases share one namespace, groups must not shadow them
+	seen := map[string]bool{}
+	for i, project := range c.Projects {
+		field := fmt.Sprintf("projects[%d]", i)
+		if project.Name == "" {
+			add("%s: name is required", field)
+		} else {
+			field = fmt.Sprintf("projects[%d] (%s)", i, project.Name)
+			if seen[strings.ToLower(project.Name)] {
+				add("%s: duplicate project name", field)
+			}
+			seen[strings.ToLower(project.Name)] = true
+		}
+		for _, alias := range project.Aliases {
+			switch {
+			case alias == "" || strings.ContainsAny(alias, ". \t"):
+				add("%s: invalid alias %q, aliases cannot be empty or contain dots or spaces", field, alias)
+			case seen[strings.ToLower(alias)]:
+				add("%s: alias %q is already a project name or alias", field, alias)
+			}
+			seen[strings.ToLower(alias)] = true
+		}
+		if project.Path == "" {
+			add("%s: path is required", field)
+		}
+		for _, path := range project.Paths {
+			if strings.TrimSpace(path) == "" {
+				add("%s: paths must not contain empty globs", field)
+			}
+		}
+		if err := validateRemotes(project.Remote, project.PushRemotes); err != nil {
+			add("%s: %v", field, err)
+		}
+		if project.InitialVersion != "" {
+			if err := utils.ValidateInitialVersion(project.InitialVersion); err != nil {
+				add("%s: initial_version: %v", field, err)
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(c.Groups))
+	for group := range c.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		if seen[strings.ToLower(group)] {
+			add("groups.%s: the group name is already a project name or alias", group)
+		}
+		if len(c.Groups[group]) == 0 {
+			add("groups.%s: a group needs at least one project", group)
+		}
+		for _, member := range c.Groups[group] {
+			if c.FindProject(member) == nil {
+				add("groups.%s: unknown project %q", group, member)
+			}
+		}
+	}
+
+	if err := validateRemotes(c.Remote, c.PushRemotes); err != nil {
+		add("%v", err)
+	}
+	if c.InitialVersion != "" {
+		if err := utils.ValidateInitialVersion(c.InitialVersion); err != nil {
+			add("initial_version: %v", err)
+		}
+	}
+	if _, err := utils.NewBranchRules(c.Branches); err != nil {
+		add("branches: %v", err)
+	}
+	if _, err := utils.NewCommitTaxonomy(c.Changelog.Types, c.Changelog.Exclude); err != nil {
+		add("changelog: %v", err)
+	}
+	if c.Lint.MaxHeaderLength < 0 {
+		add("lint.max_header_length must not be negative")
+	}
+
+	return errs
+}
+
+// validateRemotes checks that remote names are git remote names
+func validateRemotes(remote string, pushRemotes []string) error {
+	if strings.ContainsAny(remote, " \t") {
+		return fmt.Errorf("remote: invalid remote name %q", remote)
+	}
+	for _, mirror := range pushRemotes {
+		if mirror == "" || strings.ContainsAny(mirror, " \t") {
+			return fmt.Errorf("push_remotes: invalid remote name %q", mirror)
+		}
+	}
+	return nil
+}
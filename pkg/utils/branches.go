@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Branch types with a special meaning for tagging
+const (
+	BranchTypeRelease = "release"
+	BranchTypeMain    = "main"
+	BranchTypeCustom  = "custom"
+)
+
+// BranchRule maps branch names matching a glob pattern or regular expression
+// to a branch type, a bump type and the environments it may be tagged for
+type BranchRule struct {
+	Pattern      string   `mapstructure:"pattern"`
+	Regex        string   `mapstructure:"regex"`
+	Type         string   `mapstructure:"type"`
+	Bump         BumpType `mapstructure:"bump"`
+	Environments []string `mapstructure:"environments"`
+	Strategy     string   `mapstructure:"strategy"`
+
+	matcher *regexp.Regexp
+}
+
+// BranchMatch is the rule a branch matched and the name extracted from it
+type BranchMatch struct {
+	Rule BranchRule
+	Name string
+}
+
+// BranchRules is an ordered list of branch rules; the first match wins
+type BranchRules []BranchRule
+
+// DefaultBranchRules returns the built-in git flow branch rules
+func DefaultBranchRules() []BranchRule {
+	return []BranchRule{
+		{Pattern: "feature/*", Type: "feature", Bump: BumpMinor, Strategy: "minor version bump (new features)"},
+		{Pattern: "feat/*", Type: "feature", Bump: BumpMinor, Strategy: "minor version bump (new features)"},
+		{Pattern: "hotfix/*", Type: "hotfix", Bump: BumpPatch, Strategy: "patch version bump (bug fixes)"},
+		{Pattern: "fix/*", Type: "hotfix", Bump: BumpPatch, Strategy: "patch version bump (bug fixes)"},
+		{Pattern: "release/*", Type: BranchTypeRelease, Strategy: "prepare for release tagging"},
+		{Regex: `^release_(\d+\.\d+)`, Type: BranchTypeRelease, Strategy: "prepare for release tagging"},
+		{Pattern: "develop", Type: "develop", Bump: BumpAuto, Strategy: "analyze commits for bump type"},
+		{Pattern: "development", Type: "develop", Bump: BumpAuto, Strategy: "analyze commits for bump type"},
+		{Pattern: "main", Type: BranchTypeMain, Bump: BumpAuto, Strategy: "analyze commits for bump type"},
+		{Pattern: "master", Type: BranchTypeMain, Bump: BumpAuto, Strategy: "analyze commits for bump type"},
+		{Pattern: "bugfix/*", Type: "bugfix", Bump: BumpPatch, Strategy: "patch version bump (bug fixes)"},
+		{Pattern: "chore/*", Type: "chore", Bump: BumpPatch, Strategy: "patch version bump (maintenance)"},
+	}
+}
+
+// DefaultBranchRuleSet returns the rules built from the default branch rules only
+func DefaultBranchRuleSet() BranchRules {
+	rules, _ := NewBranchRules(nil)
+	return rules
+}
+
+// NewBranchRules builds branch rules from the configured rules followed by the
+// defaults, so configured rules take precedence
+func NewBranchRules(configured []BranchRule) (BranchRules, error) {
+	all := append(append([]BranchRule{}, configured...), DefaultBranchRules()...)
+
+	var rules BranchRules
+	for i, rule := range all {
+		if (rule.Pattern == "") == (rule.Regex == "") {
+			return nil, fmt.Errorf("branch rule %d: exactly one of pattern or regex is required", i+1)
+		}
+
+		rule.Type = strings.ToLower(strings.TrimSpace(rule.Type))
+		if rule.Type == "" {
+			return nil, fmt.Errorf("branch rule %s: type is required", rule.source())
+		}
+
+		switch rule.Bump {
+		case "", BumpMajor, BumpMinor, BumpPatch, BumpAuto:
+		default:
+			return nil, fmt.Errorf("branch rule %s: unsupported bump type: %s", rule.source(), rule.Bump)
+		}
+
+		for _, env := range rule.Environments {
+			if !ContainsString(ENVS, env) {
+				return nil, fmt.Errorf("branch rule %s: invalid environment '%s'. Valid environments: %v", rule.source(), env, ENVS)
+			}
+		}
+
+		expr := rule.Regex
+		if rule.Pattern != "" {
+			expr = globToRegex(rule.Pattern)
+		}
+		matcher, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("branch rule %s: invalid regex: %v", rule.source(), err)
+		}
+		rule.matcher = matcher
+
+		if rule.Strategy == "" {
+			rule.Strategy = defaultBranchStrategy(rule)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Match returns the first rule matching a branch and the name captured by the
+// first wildcard or regex group. A branch matching no rule is a custom branch
+// whose bump is detected from commits.
+func (r BranchRules) Match(branch string) BranchMatch {
+	for _, rule := range r {
+		groups := rule.matcher.FindStringSubmatch(branch)
+		if groups == nil {
+			continue
+		}
+
+		match := BranchMatch{Rule: rule}
+		if index := rule.matcher.SubexpIndex("name"); index > 0 {
+			match.Name = groups[index]
+		} else if len(groups) > 1 {
+			match.Name = groups[1]
+		}
+		return match
+	}
+
+	return BranchMatch{Rule: BranchRule{
+		Type:     BranchTypeCustom,
+		Bump:     BumpAuto,
+		Strategy: "analyze commits or manual specification",
+	}}
+}
+
+// AllowsEnvironment reports whether a branch matching the rule may be tagged
+// for an environment; a rule without environments allows all of them
+func (r BranchRule) AllowsEnvironment(env string) bool {
+	return len(r.Environments) == 0 || ContainsString(r.Environments, env)
+}
+
+// source describes the rule in error messages
+func (r BranchRule) source() string {
+	if r.Pattern != "" {
+		return fmt.Sprintf("%q", r.Pattern)
+	}
+	return fmt.Sprintf("/%s/", r.Regex)
+}
+
+// globToRegex converts a branch glob into an anchored regular expression
+// where each '*' is a capture group matching any characters, including '/'
+func globToRegex(pattern string) string {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, char := range pattern {
+		switch char {
+		case '*':
+			expr.WriteString("(.*)")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	expr.WriteString("$")
+	return expr.String()
+}
+
+// defaultBranchStrategy describes what a rule without a strategy does
+func defaultBranchStrategy(rule BranchRule) string {
+	switch rule.Bump {
+	case BumpAuto:
+		return "analyze commits for bump type"
+	case "":
+		if rule.Type == BranchTypeRelease {
+			return "prepare for release tagging"
+		}
+		return "manual specification"
+	default:
+		return fmt.Sprintf("%s version bump", rule.Bump)
+	}
+}
+
+// ReleaseBranchName returns the release_X.Y branch of a version
+func ReleaseBranchName(version string) (string, error) {
+	sv, err := ParseSemanticVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("release_%d.%d", sv.Major, sv.Minor), nil
+}
+
+// ReleaseLine returns the X.Y version line of a release_X.Y branch
+func ReleaseLine(branch string) (string, bool) {
+	matches := ReleaseBranchPattern.FindStringSubmatch(branch)
+	if matches == nil {
+		return "", false
+	}
+	return matches[1] + "." + matches[2], true
+}
+
+// LatestHotFixTag returns the newest hot fix tag (X.Y.Z-N.M) of a release
+// line X.Y among tags, or an empty string when there is none
+func LatestHotFixTag(tags []string, line string) string {
+	latest := ""
+	for _, tag := range tags {
+		info, err := ParseTag(tag)
+		if err != nil || !info.IsHotFix() || !strings.HasPrefix(info.Version, line+".") {
+			continue
+		}
+		if latest == "" {
+			latest = tag
+			continue
+		}
+		if cmp, err := CompareTags(tag, latest); err == nil && cmp > 0 {
+			latest = tag
+		}
+	}
+	return latest
+}
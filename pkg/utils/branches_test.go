@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultBranchRulesMatch(t *testing.T) {
+	rules := DefaultBranchRuleSet()
+
+	tests := []struct {
+		branch   string
+		wantType string
+		wantBump BumpType
+		wantName string
+	}{
+		{"feature/user-auth", "feature", BumpMinor, "user-auth"},
+		{"Feature/user-auth", "feature", BumpMinor, "user-auth"},
+		{"feat/team/login", "feature", BumpMinor, "team/login"},
+		{"hotfix/urgent", "hotfix", BumpPatch, "urgent"},
+		{"release/1.2.0", BranchTypeRelease, "", "1.2.0"},
+		{"release_1.2", BranchTypeRelease, "", "1.2"},
+		{"main", BranchTypeMain, BumpAuto, ""},
+		{"develop", "develop", BumpAuto, ""},
+		{"experiment", BranchTypeCustom, BumpAuto, ""},
+		{"mainline", BranchTypeCustom, BumpAuto, ""},
+	}
+
+	for _, tt := range tests {
+		match := rules.Match(tt.branch)
+		if match.Rule.Type != tt.wantType || match.Rule.Bump != tt.wantBump || match.Name != tt.wantName {
+			t.Errorf("Match(%q) = %s/%s/%q, want %s/%s/%q", tt.branch,
+				match.Rule.Type, match.Rule.Bump, match.Name, tt.wantType, tt.wantBump, tt.wantName)
+		}
+	}
+}
+
+func TestNewBranchRulesConfigured(t *testing.T) {
+	rules, err := NewBranchRules([]BranchRule{
+		{Pattern: "feature/*", Type: "feature", Bump: BumpPatch, Environments: []string{"dev"}},
+		{Regex: `^(?P<team>\w+)/(?P<name>JIRA-\d+)`, Type: "ticket", Bump: BumpMinor},
+		{Pattern: "rc-*", Type: "Release"},
+	})
+	if err != nil {
+		t.Fatalf("NewBranchRules unexpected error: %v", err)
+	}
+
+	feature := rules.Match("feature/login")
+	if feature.Rule.Bump != BumpPatch || feature.Rule.Strategy != "patch version bump" {
+		t.Errorf("configured rule should take precedence, got %+v", feature.Rule)
+	}
+	if !feature.Rule.AllowsEnvironment("dev") || feature.Rule.AllowsEnvironment("production2") {
+		t.Errorf("feature rule should only allow dev, got %v", feature.Rule.Environments)
+	}
+
+	ticket := rules.Match("payments/JIRA-42-refunds")
+	if ticket.Rule.Type != "ticket" || ticket.Name != "JIRA-42" {
+		t.Errorf("named group should set the name, got %s %q", ticket.Rule.Type, ticket.Name)
+	}
+
+	rc := rules.Match("rc-2.0")
+	if rc.Rule.Type != BranchTypeRelease || rc.Rule.Strategy != "prepare for release tagging" {
+		t.Errorf("rc-* should be a release branch, got %+v", rc.Rule)
+	}
+
+	if hotfix := rules.Match("hotfix/urgent"); hotfix.Rule.Type != "hotfix" {
+		t.Errorf("default rules should still apply, got %s", hotfix.Rule.Type)
+	}
+}
+
+func TestNewBranchRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    BranchRule
+		wantErr string
+	}{
+		{"no pattern", BranchRule{Type: "feature"}, "exactly one of pattern or regex"},
+		{"pattern and regex", BranchRule{Pattern: "a/*", Regex: "^a/", Type: "feature"}, "exactly one of pattern or regex"},
+		{"no type", BranchRule{Pattern: "a/*"}, "type is required"},
+		{"bad bump", BranchRule{Pattern: "a/*", Type: "a", Bump: "huge"}, "unsupported bump type"},
+		{"bad environment", BranchRule{Pattern: "a/*", Type: "a", Environments: []string{"qa"}}, "invalid environment"},
+		{"bad regex", BranchRule{Regex: "^a/(", Type: "a"}, "invalid regex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBranchRules([]BranchRule{tt.rule})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewBranchRules() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReleaseBranchName(t *testing.T) {
+	if got, err := ReleaseBranchName("1.3.0"); err != nil || got != "release_1.3" {
+		t.Errorf("ReleaseBranchName(1.3.0) = %q, %v", got, err)
+	}
+	if _, err := ReleaseBranchName("1.3"); err == nil {
+		t.Error("ReleaseBranchName(1.3) should fail")
+	}
+
+	branch, _ := ReleaseBranchName("10.25.4")
+	if line, ok := ReleaseLine(branch); !ok || line != "10.25" {
+		t.Errorf("ReleaseLine(%s) = %q, %t", branch, line, ok)
+	}
+	if _, ok := ReleaseLine("release/1.2.0"); ok {
+		t.Error("ReleaseLine(release/1.2.0) should not match")
+	}
+}
+
+func TestLatestHotFixTag(t *testing.T) {
+	tags := []string{
+		"dev_1.2.0-1",
+		"stg6_1.2.0-1.1",
+		"production2_1.2.0-1.2",
+		"production2_1.2.1-1.1",
+		"production2_1.20.0-1.5",
+		"stg6_1.3.0-1.1",
+	}
+
+	if got := LatestHotFixTag(tags, "1.2"); got != "production2_1.2.1-1.1" {
+		t.Errorf("LatestHotFixTag(1.2) = %q", got)
+	}
+	if got := LatestHotFixTag(tags, "1.4"); got != "" {
+		t.Errorf("LatestHotFixTag(1.4) = %q, want none", got)
+	}
+}
@@ -0,0 +1,323 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ConventionalCommitPattern matches a conventional commit header: type(scope)!: description
+var ConventionalCommitPattern = regexp.MustCompile(`^(\w+)(\([^)]+\))?(!)?: (.+)$`)
+
+// conventionalHeaderPattern matches the type prefix of a conventional commit header
+var conventionalHeaderPattern = regexp.MustCompile(`^(\w+)(\([^)]*\))?(!)?:`)
+
+// breakingHeaderPattern matches a header whose type or scope is followed by '!'
+var breakingHeaderPattern = regexp.MustCompile(`^\w+(\([^)]*\))?!:`)
+
+// breakingFooterPattern matches a BREAKING CHANGE footer line
+var breakingFooterPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// OtherCommitType is the type assigned to commits that match no configured type
+const OtherCommitType = "other"
+
+// CommitType describes one commit type of the changelog taxonomy
+type CommitType struct {
+	Name     string   `mapstructure:"name"`
+	Title    string   `mapstructure:"title"`
+	Order    int      `mapstructure:"order"`
+	Aliases  []string `mapstructure:"aliases"`
+	Keywords []string `mapstructure:"keywords"`
+	Hidden   bool     `mapstructure:"hidden"`
+	Bump     BumpType `mapstructure:"bump"`
+}
+
+// CommitMessage is a commit split into its subject line and body. Message
+// keeps the full message as written, which linting checks line by line.
+type CommitMessage struct {
+	Hash    string
+	Subject string
+	Body    string
+	Message string
+}
+
+// ParseCommitMessage splits a full commit message into subject and body
+func ParseCommitMessage(hash, message string) CommitMessage {
+	parts := strings.SplitN(strings.TrimSpace(message), "\n", 2)
+	commit := CommitMessage{Hash: hash, Subject: strings.TrimSpace(parts[0]), Message: message}
+	if len(parts) == 2 {
+		commit.Body = strings.TrimSpace(parts[1])
+	}
+	return commit
+}
+
+// IsBreaking reports whether the commit is a breaking change: its header
+// carries '!' after the type or scope, or a line of its body starts with a
+// BREAKING CHANGE footer
+func (c CommitMessage) IsBreaking() bool {
+	return breakingHeaderPattern.MatchString(c.Subject) || breakingFooterPattern.MatchString(c.Body)
+}
+
+// BumpTrigger is a commit that led to a bump decision and the rule it matched
+type BumpTrigger struct {
+	Commit CommitMessage
+	Rule   string
+	Bump   BumpType
+}
+
+// BumpDecision is the suggested bump with the reasoning behind it
+type BumpDecision struct {
+	Bump       BumpType
+	Triggers   []BumpTrigger
+	Analyzed   int
+	Excluded   int
+	Adjustment string
+}
+
+// ApplyInitialDevelopment lowers a major decision to minor while version is
+// still 0.x and records why
+func (d *BumpDecision) ApplyInitialDevelopment(version string) {
+	if bump, lowered := ApplyInitialDevelopment(version, d.Bump); lowered {
+		d.Bump = bump
+		d.Adjustment = fmt.Sprintf("initial development: %s is below 1.0.0, so breaking changes bump minor", version)
+	}
+}
+
+// CommitTaxonomy is the set of commit types and exclusion patterns shared by
+// changelog generation and bump detection
+type CommitTaxonomy struct {
+	Types   []CommitType
+	Exclude []*regexp.Regexp
+}
+
+// DefaultCommitTypes returns the built-in commit types
+func DefaultCommitTypes() []CommitType {
+	return []CommitType{
+		{Name: "feat", Title: "🚀 Features", Order: 10, Aliases: []string{"feature"},
+			Keywords: []string{"add", "adds", "added", "implement", "implements", "introduce", "introduces", "feature"}, Bump: BumpMinor},
+		{Name: "fix", Title: "🐛 Bug Fixes", Order: 20, Aliases: []string{"bugfix", "hotfix"},
+			Keywords: []string{"fix", "fixes", "fixed", "resolve", "resolves", "resolved", "bugfix", "hotfix"}, Bump: BumpPatch},
+		{Name: "perf", Title: "⚡ Performance", Order: 30, Aliases: []string{"performance"},
+			Keywords: []string{"perf", "optimize", "optimise", "speed"}, Bump: BumpPatch},
+		{Name: "refactor", Title: "♻️ Refactoring", Order: 40,
+			Keywords: []string{"refactor", "refactors", "refactored", "cleanup", "restructure"}, Bump: BumpPatch},
+		{Name: "docs", Title: "📚 Documentation", Order: 50, Aliases: []string{"doc"},
+			Keywords: []string{"doc", "docs", "document", "documented", "readme"}, Bump: BumpPatch},
+		{Name: "style", Title: "💄 Style", Order: 60,
+			Keywords: []string{"style", "format", "formatted", "lint"}, Bump: BumpPatch},
+		{Name: "test", Title: "🧪 Tests", Order: 70, Aliases: []string{"tests"},
+			Keywords: []string{"test", "tests"}, Bump: BumpPatch},
+		{Name: "chore", Title: "🔧 Chores", Order: 80, Aliases: []string{"build", "ci"},
+			Keywords: []string{"chore", "bump"}, Bump: BumpPatch},
+		{Name: OtherCommitType, Title: "📝 Other Changes", Order: 1000, Bump: BumpPatch},
+	}
+}
+
+// DefaultExcludePatterns returns the built-in commit exclusion patterns
+func DefaultExcludePatterns() []string {
+	return []string{
+		`^Merge (branch|pull request|remote-tracking branch|tag) `,
+		`^(chore|build)\(deps(-dev)?\)`,
+	}
+}
+
+// DefaultCommitTaxonomy returns the taxonomy built from the default types and exclusions
+func DefaultCommitTaxonomy() *CommitTaxonomy {
+	taxonomy, _ := NewCommitTaxonomy(nil, nil)
+	return taxonomy
+}
+
+// NewCommitTaxonomy builds a taxonomy from the defaults merged with configured
+// types and exclusions. A configured type replaces the default type with the
+// same name; other configured types are added. A nil exclude list keeps the
+// default exclusions.
+func NewCommitTaxonomy(types []CommitType, exclude []string) (*CommitTaxonomy, error) {
+	merged := DefaultCommitTypes()
+	for _, configured := range types {
+		configured.Name = strings.ToLower(strings.TrimSpace(configured.Name))
+		if configured.Name == "" {
+			return nil, fmt.Errorf("commit type without name")
+		}
+		if configured.Bump == "" {
+			configured.Bump = BumpPatch
+		}
+		if configured.Bump != BumpMajor && configured.Bump != BumpMinor && configured.Bump != BumpPatch {
+			return nil, fmt.Errorf("commit type %s: unsupported bump type: %s", configured.Name, configured.Bump)
+		}
+
+		replaced := false
+		for i, existing := range merged {
+			if existing.Name == configured.Name {
+				merged[i] = configured
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, configured)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Order < merged[j].Order
+	})
+
+	if exclude == nil {
+		exclude = DefaultExcludePatterns()
+	}
+
+	taxonomy := &CommitTaxonomy{Types: merged}
+	for _, pattern := range exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+		}
+		taxonomy.Exclude = append(taxonomy.Exclude, re)
+	}
+
+	return taxonomy, nil
+}
+
+// Resolve looks up a commit type by name or alias
+func (t *CommitTaxonomy) Resolve(name string) (CommitType, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, commitType := range t.Types {
+		if commitType.Name == name || ContainsString(commitType.Aliases, name) {
+			return commitType, true
+		}
+	}
+	return CommitType{}, false
+}
+
+// Lookup returns the commit type for a name, falling back to the "other" type
+func (t *CommitTaxonomy) Lookup(name string) CommitType {
+	if commitType, ok := t.Resolve(name); ok {
+		return commitType
+	}
+	if other, ok := t.Resolve(OtherCommitType); ok {
+		return other
+	}
+	return CommitType{Name: OtherCommitType, Bump: BumpPatch}
+}
+
+// IsExcluded reports whether a commit message matches an exclusion pattern
+func (t *CommitTaxonomy) IsExcluded(message string) bool {
+	for _, re := range t.Exclude {
+		if re.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify returns the canonical type of a commit message. Conventional commit
+// headers are resolved through the type names and aliases; other messages are
+// classified by their first word only, so words like "update" or "issue" later
+// in the message do not change the type.
+func (t *CommitTaxonomy) Classify(message string) string {
+	if matches := ConventionalCommitPattern.FindStringSubmatch(message); matches != nil {
+		if commitType, ok := t.Resolve(matches[1]); ok {
+			return commitType.Name
+		}
+	}
+
+	fields := strings.Fields(strings.ToLower(message))
+	if len(fields) == 0 {
+		return OtherCommitType
+	}
+	firstWord := strings.Trim(fields[0], ":;,.!()[]")
+
+	for _, commitType := range t.Types {
+		if ContainsString(commitType.Keywords, firstWord) {
+			return commitType.Name
+		}
+	}
+
+	return OtherCommitType
+}
+
+// DetectBumpType analyzes commit messages with this taxonomy to suggest version bump type
+func (t *CommitTaxonomy) DetectBumpType(commits []string) BumpType {
+	messages := make([]CommitMessage, 0, len(commits))
+	for _, commit := range commits {
+		messages = append(messages, ParseCommitMessage("", commit))
+	}
+	return t.DecideBump(messages).Bump
+}
+
+// DecideBump analyzes commits with this taxonomy and returns the suggested
+// bump together with the commits that triggered it and the rule each matched.
+// A commit is breaking when its header carries '!' after the type or scope,
+// or when a line of its message starts with a BREAKING CHANGE footer.
+func (t *CommitTaxonomy) DecideBump(commits []CommitMessage) BumpDecision {
+	decision := BumpDecision{Bump: BumpPatch}
+	var evaluated []BumpTrigger
+
+	for _, commit := range commits {
+		if t.IsExcluded(commit.Subject) {
+			decision.Excluded++
+			continue
+		}
+		decision.Analyzed++
+
+		trigger := t.evaluateBump(commit)
+		evaluated = append(evaluated, trigger)
+		if bumpRank(trigger.Bump) > bumpRank(decision.Bump) {
+			decision.Bump = trigger.Bump
+		}
+	}
+
+	for _, trigger := range evaluated {
+		if trigger.Bump == decision.Bump {
+			decision.Triggers = append(decision.Triggers, trigger)
+		}
+	}
+
+	return decision
+}
+
+// evaluateBump returns the bump a single commit asks for and the rule that decided it
+func (t *CommitTaxonomy) evaluateBump(commit CommitMessage) BumpTrigger {
+	trigger := BumpTrigger{Commit: commit, Bump: BumpPatch, Rule: "not a conventional commit, defaults to patch"}
+
+	if breakingHeaderPattern.MatchString(commit.Subject) {
+		trigger.Bump, trigger.Rule = BumpMajor, "'!' after the type marks a breaking change"
+		return trigger
+	}
+	if breakingFooterPattern.MatchString(commit.Body) {
+		trigger.Bump, trigger.Rule = BumpMajor, "BREAKING CHANGE footer"
+		return trigger
+	}
+
+	matches := conventionalHeaderPattern.FindStringSubmatch(commit.Subject)
+	if matches == nil {
+		return trigger
+	}
+
+	commitType, ok := t.Resolve(matches[1])
+	if !ok {
+		trigger.Rule = fmt.Sprintf("unknown type '%s', defaults to patch", matches[1])
+		return trigger
+	}
+
+	trigger.Bump = commitType.Bump
+	if trigger.Bump == "" {
+		trigger.Bump = BumpPatch
+	}
+	trigger.Rule = fmt.Sprintf("type '%s' bumps %s", commitType.Name, trigger.Bump)
+	return trigger
+}
+
+// bumpRank orders bump types from patch to major
+func bumpRank(bump BumpType) int {
+	switch bump {
+	case BumpMajor:
+		return 3
+	case BumpMinor:
+		return 2
+	case BumpPatch:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitTaxonomyResolve(t *testing.T) {
+	taxonomy := DefaultCommitTaxonomy()
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"feat", "feat", true},
+		{"feature", "feat", true}, // alias
+		{"bugfix", "fix", true},   // alias
+		{"FIX", "fix", true},      // case insensitive
+		{"ci", "chore", true},     // alias
+		{"unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := taxonomy.Resolve(tt.name)
+		if ok != tt.wantOk || got.Name != tt.want {
+			t.Errorf("Resolve(%q) = %q, %t, want %q, %t", tt.name, got.Name, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestCommitTaxonomyClassify(t *testing.T) {
+	taxonomy := DefaultCommitTaxonomy()
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"feat: add login", "feat"},
+		{"feature(api): add login", "feat"},
+		{"bugfix: handle nil", "fix"},
+		{"Fix issue with login", "fix"},
+		{"Add issue template", "feat"},                   // "issue" no longer means fix
+		{"Update dependencies", OtherCommitType},         // "update" no longer means chore
+		{"Improve error on bug report", OtherCommitType}, // keywords only count as first word
+		{"Refactor tag parsing", "refactor"},             // first word keyword
+		{"docs: update README", "docs"},                  // conventional type wins
+		{"unknowntype: something", OtherCommitType},      // unknown conventional type
+		{"", OtherCommitType},
+	}
+
+	for _, tt := range tests {
+		got := taxonomy.Classify(tt.message)
+		if got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestCommitTaxonomyIsExcluded(t *testing.T) {
+	taxonomy := DefaultCommitTaxonomy()
+
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"Merge branch 'main' into feature/x", true},
+		{"Merge pull request #12 from org/branch", true},
+		{"chore(deps): bump cobra to 1.9.1", true},
+		{"build(deps-dev): bump lint", true},
+		{"chore: tidy go.mod", false},
+		{"feat: merge configs", false},
+	}
+
+	for _, tt := range tests {
+		got := taxonomy.IsExcluded(tt.message)
+		if got != tt.want {
+			t.Errorf("IsExcluded(%q) = %t, want %t", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNewCommitTaxonomy(t *testing.T) {
+	taxonomy, err := NewCommitTaxonomy([]CommitType{
+		{Name: "fix", Title: "Fixes", Order: 5, Aliases: []string{"patch"}},
+		{Name: "security", Title: "Security", Order: 15, Bump: BumpMinor},
+		{Name: "chore", Order: 80, Hidden: true},
+	}, []string{`^WIP`})
+	if err != nil {
+		t.Fatalf("NewCommitTaxonomy unexpected error: %v", err)
+	}
+
+	if taxonomy.Types[0].Name != "fix" {
+		t.Errorf("Expected configured order to put fix first, got %q", taxonomy.Types[0].Name)
+	}
+
+	if got := taxonomy.Lookup("patch"); got.Name != "fix" || got.Title != "Fixes" {
+		t.Errorf("Lookup(patch) = %+v, want configured fix type", got)
+	}
+
+	if _, ok := taxonomy.Resolve("bugfix"); ok {
+		t.Error("Configured fix type should replace default aliases")
+	}
+
+	if got := taxonomy.Lookup("security"); got.Bump != BumpMinor {
+		t.Errorf("Lookup(security).Bump = %q, want %q", got.Bump, BumpMinor)
+	}
+
+	if !taxonomy.Lookup("chore").Hidden {
+		t.Error("Expected chore to be hidden")
+	}
+
+	if taxonomy.IsExcluded("Merge branch 'main'") {
+		t.Error("Configured exclusions should replace the defaults")
+	}
+	if !taxonomy.IsExcluded("WIP: half done") {
+		t.Error("Expected WIP commit to be excluded")
+	}
+
+	if _, err := NewCommitTaxonomy([]CommitType{{Name: ""}}, nil); err == nil {
+		t.Error("Expected error for type without name")
+	}
+	if _, err := NewCommitTaxonomy([]CommitType{{Name: "x", Bump: "huge"}}, nil); err == nil {
+		t.Error("Expected error for unsupported bump type")
+	}
+	if _, err := NewCommitTaxonomy(nil, []string{"("}); err == nil {
+		t.Error("Expected error for invalid exclude pattern")
+	}
+}
+
+func TestCommitTaxonomyDetectBumpType(t *testing.T) {
+	taxonomy, err := NewCommitTaxonomy([]CommitType{
+		{Name: "security", Order: 15, Bump: BumpMinor},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewCommitTaxonomy unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		commits []string
+		want    BumpType
+	}{
+		{[]string{"feature: add login"}, BumpMinor},     // alias of feat
+		{[]string{"security: rotate keys"}, BumpMinor},  // configured type
+		{[]string{"Merge branch 'feat: x'"}, BumpPatch}, // excluded
+		{[]string{"fix: bug", "feat!: new api"}, BumpMajor},
+	}
+
+	for _, tt := range tests {
+		got := taxonomy.DetectBumpType(tt.commits)
+		if got != tt.want {
+			t.Errorf("DetectBumpType(%v) = %q, want %q", tt.commits, got, tt.want)
+		}
+	}
+}
+
+func TestCommitTaxonomyDecideBump(t *testing.T) {
+	taxonomy := DefaultCommitTaxonomy()
+
+	tests := []struct {
+		name         string
+		commits      []CommitMessage
+		want         BumpType
+		wantTriggers []string
+		wantRule     string
+	}{
+		{
+			name: "breaking footer in body",
+			commits: []CommitMessage{
+				{Hash: "a", Subject: "feat: add login"},
+				{Hash: "b", Subject: "refactor(api): split handlers", Body: "Details\n\nBREAKING CHANGE: handlers moved"},
+			},
+			want:         BumpMajor,
+			wantTriggers: []string{"b"},
+			wantRule:     "BREAKING CHANGE footer",
+		},
+		{
+			name:         "breaking marker after scope",
+			commits:      []CommitMessage{{Hash: "a", Subject: "feat(api)!: drop v1"}},
+			want:         BumpMajor,
+			wantTriggers: []string{"a"},
+			wantRule:     "'!' after the type marks a breaking change",
+		},
+		{
+			name: "breaking word elsewhere is not breaking",
+			commits: []CommitMessage{
+				{Hash: "a", Subject: "fix: handle BREAKING input"},
+				{Hash: "b", Subject: "docs: explain why x!: y is odd"},
+				{Hash: "c", Subject: "fix: nothing", Body: "mentions BREAKING CHANGE: inline"},
+			},
+			want:         BumpPatch,
+			wantTriggers: []string{"a", "b", "c"},
+			wantRule:     "type 'fix' bumps patch",
+		},
+		{
+			name:         "breaking footer as the subject is not a footer",
+			commits:      []CommitMessage{{Hash: "a", Subject: "BREAKING CHANGE: drop v1"}},
+			want:         BumpPatch,
+			wantTriggers: []string{"a"},
+			wantRule:     "not a conventional commit, defaults to patch",
+		},
+		{
+			name: "all minor triggers are reported",
+			commits: []CommitMessage{
+				{Hash: "a", Subject: "feat: one"},
+				{Hash: "b", Subject: "fix: two"},
+				{Hash: "c", Subject: "feature: three"},
+			},
+			want:         BumpMinor,
+			wantTriggers: []string{"a", "c"},
+			wantRule:     "type 'feat' bumps minor",
+		},
+		{
+			name:         "excluded commits are counted but ignored",
+			commits:      []CommitMessage{{Hash: "a", Subject: "Merge branch 'feat!: x'"}},
+			want:         BumpPatch,
+			wantTriggers: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := taxonomy.DecideBump(tt.commits)
+			if decision.Bump != tt.want {
+				t.Errorf("DecideBump().Bump = %q, want %q", decision.Bump, tt.want)
+			}
+
+			var hashes []string
+			for _, trigger := range decision.Triggers {
+				hashes = append(hashes, trigger.Commit.Hash)
+			}
+			if strings.Join(hashes, ",") != strings.Join(tt.wantTriggers, ",") {
+				t.Errorf("DecideBump() triggers = %v, want %v", hashes, tt.wantTriggers)
+			}
+			if tt.wantRule != "" && decision.Triggers[0].Rule != tt.wantRule {
+				t.Errorf("DecideBump() rule = %q, want %q", decision.Triggers[0].Rule, tt.wantRule)
+			}
+			if decision.Analyzed+decision.Excluded != len(tt.commits) {
+				t.Errorf("Analyzed %d + excluded %d != %d commits", decision.Analyzed, decision.Excluded, len(tt.commits))
+			}
+		})
+	}
+}
+
+func TestParseCommitMessage(t *testing.T) {
+	commit := ParseCommitMessage("abc", "feat: add login\n\nLonger body\n")
+	if commit.Hash != "abc" || commit.Subject != "feat: add login" || commit.Body != "Longer body" {
+		t.Errorf("ParseCommitMessage() = %+v", commit)
+	}
+	if commit.Message != "feat: add login\n\nLonger body\n" {
+		t.Errorf("ParseCommitMessage() should keep the full message, got %q", commit.Message)
+	}
+}
+
+func TestCommitMessageIsBreaking(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected bool
+	}{
+		{"feat(api)!: drop v1", true},
+		{"feat: drop v1\n\nBREAKING CHANGE: v1 is gone", true},
+		{"feat: drop v1\n\nBREAKING-CHANGE: v1 is gone", true},
+		{"fix: mention BREAKING CHANGE: in the docs", false},
+		{"fix: handle breaking change detection", false},
+		{"fix: typo\n\nSee the BREAKING CHANGE: section", false},
+	}
+
+	for _, tt := range tests {
+		if got := ParseCommitMessage("abc", tt.message).IsBreaking(); got != tt.expected {
+			t.Errorf("IsBreaking(%q) = %v, want %v", tt.message, got, tt.expected)
+		}
+	}
+}
+
+func TestBumpDecisionApplyInitialDevelopment(t *testing.T) {
+	decision := DefaultCommitTaxonomy().DecideBump([]CommitMessage{{Hash: "a", Subject: "feat!: drop v1"}})
+
+	decision.ApplyInitialDevelopment("1.2.0")
+	if decision.Bump != BumpMajor || decision.Adjustment != "" {
+		t.Errorf("Stable versions should keep major bumps, got %+v", decision)
+	}
+
+	decision.ApplyInitialDevelopment("0.3.0")
+	if decision.Bump != BumpMinor || decision.Adjustment == "" {
+		t.Errorf("Expected 0.x major bump to be lowered to minor with a reason, got %+v", decision)
+	}
+	if len(decision.Triggers) != 1 {
+		t.Errorf("Expected the triggering commit to be kept, got %+v", decision.Triggers)
+	}
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultMaxHeaderLength is the default maximum length of a commit header
+const DefaultMaxHeaderLength = 72
+
+// scopePattern matches a well-formed commit scope
+var scopePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+// LintRules configures the commit message checks on top of the commit taxonomy
+type LintRules struct {
+	MaxHeaderLength int      `mapstructure:"max_header_length"`
+	Scopes          []string `mapstructure:"scopes"`
+	RequireScope    bool     `mapstructure:"require_scope"`
+}
+
+// LintViolation is one rule a commit message breaks
+type LintViolation struct {
+	Rule    string
+	Message string
+}
+
+func (v LintViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// CleanCommitMessage strips the comment lines and verbose diff git adds to a
+// message being edited, along with trailing blank lines
+func CleanCommitMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// IsAutosquashCommit reports whether a message is a fixup!/squash!/amend! commit
+// that git rebase --autosquash folds into an earlier commit
+func IsAutosquashCommit(message string) bool {
+	for _, prefix := range []string{"fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks a commit message against the conventional commit format and this
+// taxonomy's types. Excluded and autosquash commits have no violations.
+func (t *CommitTaxonomy) Lint(message string, rules LintRules) []LintViolation {
+	message = CleanCommitMessage(message)
+	if message == "" {
+		return []LintViolation{{Rule: "empty", Message: "commit message is empty"}}
+	}
+
+	lines := strings.Split(message, "\n")
+	header := lines[0]
+	if t.IsExcluded(header) || IsAutosquashCommit(header) {
+		return nil
+	}
+
+	var violations []LintViolation
+
+	maxLength := rules.MaxHeaderLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxHeaderLength
+	}
+	if length := len([]rune(header)); length > maxLength {
+		violations = append(violations, LintViolation{Rule: "header-length",
+			Message: fmt.Sprintf("header is %d characters long, maximum is %d", length, maxLength)})
+	}
+
+	if len(lines) > 1 && lines[1] != "" {
+		violations = append(violations, LintViolation{Rule: "body-separator",
+			Message: "header must be followed by a blank line"})
+	}
+
+	matches := ConventionalCommitPattern.FindStringSubmatch(header)
+	if matches == nil {
+		return append(violations, LintViolation{Rule: "format",
+			Message: fmt.Sprintf("header %q does not match 'type(scope)!: subject'", header)})
+	}
+
+	commitType, scope, subject := matches[1], strings.Trim(matches[2], "()"), matches[4]
+
+	if resolved, ok := t.Resolve(commitType); !ok || resolved.Name == OtherCommitType {
+		violations = append(violations, LintViolation{Rule: "type",
+			Message: fmt.Sprintf("unknown type %q, expected one of: %s", commitType, strings.Join(t.typeNames(), ", "))})
+	} else if commitType != strings.ToLower(commitType) {
+		violations = append(violations, LintViolation{Rule: "type",
+			Message: fmt.Sprintf("type %q must be lower case", commitType)})
+	}
+
+	switch {
+	case scope == "" && rules.RequireScope:
+		violations = append(violations, LintViolation{Rule: "scope", Message: "scope is required"})
+	case scope != "" && len(rules.Scopes) > 0 && !ContainsString(rules.Scopes, scope):
+		violations = append(violations, LintViolation{Rule: "scope",
+			Message: fmt.Sprintf("unknown scope %q, expected one of: %s", scope, strings.Join(rules.Scopes, ", "))})
+	case scope != "" && !scopePattern.MatchString(scope):
+		violations = append(violations, LintViolation{Rule: "scope",
+			Message: fmt.Sprintf("scope %q must be lower case without spaces", scope)})
+	}
+
+	if strings.TrimSpace(subject) != subject || subject == "" {
+		violations = append(violations, LintViolation{Rule: "subject",
+			Message: "subject must not be empty or start with whitespace"})
+	} else if strings.HasSuffix(subject, ".") {
+		violations = append(violations, LintViolation{Rule: "subject",
+			Message: "subject must not end with a period"})
+	}
+
+	return violations
+}
+
+// typeNames returns the names of the taxonomy's types in order
+func (t *CommitTaxonomy) typeNames() []string {
+	names := make([]string, 0, len(t.Types))
+	for _, commitType := range t.Types {
+		if commitType.Name != OtherCommitType {
+			names = append(names, commitType.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanCommitMessage(t *testing.T) {
+	message := "feat: add login\n\nBody line   \n# Please enter the commit message\n\n" +
+		"# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n"
+
+	want := "feat: add login\n\nBody line"
+	if got := CleanCommitMessage(message); got != want {
+		t.Errorf("CleanCommitMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestCommitTaxonomyLint(t *testing.T) {
+	taxonomy := DefaultCommitTaxonomy()
+
+	tests := []struct {
+		name      string
+		message   string
+		rules     LintRules
+		wantRules []string
+	}{
+		{"valid", "feat(api): add login", LintRules{}, nil},
+		{"valid alias", "bugfix: handle nil", LintRules{}, nil},
+		{"valid breaking with body", "feat!: drop v1\n\nBREAKING CHANGE: v1 is gone", LintRules{}, nil},
+		{"merge commit skipped", "Merge branch 'main' into feature/x", LintRules{}, nil},
+		{"fixup skipped", "fixup! feat: add login", LintRules{}, nil},
+		{"comment only", "# nothing here\n", LintRules{}, []string{"empty"}},
+		{"not conventional", "Add login", LintRules{}, []string{"format"}},
+		{"unknown type", "feature-flag: add login", LintRules{}, []string{"format"}},
+		{"unconfigured type", "wip: add login", LintRules{}, []string{"type"}},
+		{"other is not a type", "other: add login", LintRules{}, []string{"type"}},
+		{"upper case type", "Feat: add login", LintRules{}, []string{"type"}},
+		{"bad scope", "feat(My API): add login", LintRules{}, []string{"scope"}},
+		{"scope not allowed", "feat(web): add login", LintRules{Scopes: []string{"api"}}, []string{"scope"}},
+		{"scope required", "feat: add login", LintRules{RequireScope: true}, []string{"scope"}},
+		{"trailing period", "fix: handle nil.", LintRules{}, []string{"subject"}},
+		{"leading space", "fix:  handle nil", LintRules{}, []string{"subject"}},
+		{"missing blank line", "fix: handle nil\nmore details", LintRules{}, []string{"body-separator"}},
+		{"header too long", "fix: " + strings.Repeat("x", 30), LintRules{MaxHeaderLength: 20}, []string{"header-length"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, violation := range taxonomy.Lint(tt.message, tt.rules) {
+				got = append(got, violation.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("Lint(%q) rules = %v, want %v", tt.message, got, tt.wantRules)
+			}
+		})
+	}
+}
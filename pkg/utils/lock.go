@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// lockPollInterval is how often a held lock is checked while waiting for it
+var lockPollInterval = 200 * time.Millisecond
+
+// lockSequence makes lock tokens unique within the process
+var lockSequence atomic.Int64
+
+// FileLock is an exclusive lock held by creating a file
+type FileLock struct {
+	Path  string
+	token string
+}
+
+// lockInfo is what a lock file tells about its holder
+type lockInfo struct {
+	pid     int
+	age     time.Duration
+	content string
+}
+
+// AcquireLock creates the lock file at path, waiting up to timeout while
+// another process holds it. A lock whose process is gone or that is older than
+// stale is taken over, so a crashed or interrupted run does not block others.
+func AcquireLock(path string, timeout, stale time.Duration) (*FileLock, error) {
+	deadline := time.Now().Add(timeout)
+	waiting := false
+
+	for {
+		token := newLockToken()
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(file, "%d %s\n", os.Getpid(), token)
+			file.Close()
+			return &FileLock{Path: path, token: token}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock %s: %v", path, err)
+		}
+
+		info, err := readLock(path)
+		if err != nil {
+			// Removed between the create attempt and the read, try again
+			continue
+		}
+		if info.stale(stale) {
+			takeOverLock(path, info, stale)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for lock %s held by pid %d", timeout, path, info.pid)
+		}
+		if !waiting {
+			fmt.Fprintf(os.Stderr, "Waiting for lock %s held by pid %d...\n", path, info.pid)
+			waiting = true
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// takeOverLock removes the stale lock seen at path. Another waiter may have
+// taken it over and created a fresh lock since it was read, so the file is
+// first moved aside and only deleted if it is still the stale one; a fresh
+// lock is put back without replacing a lock created in the meantime.
+func takeOverLock(path string, seen lockInfo, stale time.Duration) {
+	aside := fmt.Sprintf("%s.%s.stale", path, newLockToken())
+	if err := os.Rename(path, aside); err != nil {
+		// Already taken over by another waiter
+		return
+	}
+
+	info, err := readLock(aside)
+	if err == nil && (info.content != seen.content || !info.stale(stale)) {
+		os.Link(aside, path)
+	}
+	os.Remove(aside)
+}
+
+// Release removes the lock file if it is still held by this lock
+func (l *FileLock) Release() {
+	if l == nil {
+		return
+	}
+	if info, err := readLock(l.Path); err == nil && strings.HasSuffix(info.content, " "+l.token) {
+		os.Remove(l.Path)
+	}
+}
+
+// readLock returns the holder written in a lock file and the age of the file
+func readLock(path string) (lockInfo, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return lockInfo{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return lockInfo{}, err
+	}
+
+	// A lock without a pid is still being written, its pid is unknown (0)
+	info := lockInfo{age: time.Since(stat.ModTime()), content: strings.TrimSpace(string(data))}
+	if fields := strings.Fields(info.content); len(fields) > 0 {
+		info.pid, _ = strconv.Atoi(fields[0])
+	}
+	return info, nil
+}
+
+// stale reports whether the process holding the lock is gone or the lock is
+// older than the stale age
+func (i lockInfo) stale(stale time.Duration) bool {
+	return (i.pid > 0 && !processAlive(i.pid)) || i.age > stale
+}
+
+// newLockToken returns a token that identifies one lock file
+func newLockToken() string {
+	return fmt.Sprintf("%d-%d-%d", os.Getpid(), time.Now().UnixNano(), lockSequence.Add(1))
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag.lock")
+
+	lock, err := AcquireLock(path, time.Second, time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireLock unexpected error: %v", err)
+	}
+	if info, err := readLock(path); err != nil || info.pid != os.Getpid() {
+		t.Errorf("lock should hold the current pid, got %d (%v)", info.pid, err)
+	}
+
+	// A held lock of a live process times out
+	defer func(interval time.Duration) { lockPollInterval = interval }(lockPollInterval)
+	lockPollInterval = 10 * time.Millisecond
+	if _, err := AcquireLock(path, 50*time.Millisecond, time.Minute); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout while the lock is held, got %v", err)
+	}
+
+	lock.Release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Release should remove the lock file")
+	}
+}
+
+func TestAcquireLockTakesOverStaleLocks(t *testing.T) {
+	dir := t.TempDir()
+
+	// The process that wrote the lock is gone
+	dead := filepath.Join(dir, "dead.lock")
+	if err := os.WriteFile(dead, []byte("999999999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireLock(dead, 50*time.Millisecond, time.Minute); err != nil {
+		t.Errorf("a lock of a dead process should be taken over, got %v", err)
+	}
+
+	// The lock is older than the stale age
+	old := filepath.Join(dir, "old.lock")
+	if err := os.WriteFile(old, []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireLock(old, 50*time.Millisecond, time.Minute); err != nil {
+		t.Errorf("a stale lock should be taken over, got %v", err)
+	}
+}
+
+func TestAcquireLockStaleTakeoverIsExclusive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tag.lock")
+
+	defer func(interval time.Duration) { lockPollInterval = interval }(lockPollInterval)
+	lockPollInterval = time.Millisecond
+
+	for round := 0; round < 20; round++ {
+		if err := os.WriteFile(path, []byte("999999999\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// All waiters see the same stale lock, only one may hold it at a time
+		var holders atomic.Int32
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				lock, err := AcquireLock(path, 5*time.Second, time.Minute)
+				if err != nil {
+					t.Errorf("AcquireLock unexpected error: %v", err)
+					return
+				}
+				if n := holders.Add(1); n != 1 {
+					t.Errorf("lock held by %d waiters at once", n)
+				}
+				time.Sleep(5 * time.Millisecond)
+				holders.Add(-1)
+				lock.Release()
+			}()
+		}
+		close(start)
+		wg.Wait()
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no lock files left behind, got %d", len(entries))
+	}
+}
+
+func TestTakeOverLockKeepsFreshLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag.lock")
+	if err := os.WriteFile(path, []byte("999999999\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seen, err := readLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another waiter takes the stale lock over before this one gets to it
+	lock, err := AcquireLock(path, time.Second, time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireLock unexpected error: %v", err)
+	}
+	takeOverLock(path, seen, time.Minute)
+
+	info, err := readLock(path)
+	if err != nil || !strings.HasSuffix(info.content, " "+lock.token) {
+		t.Errorf("the fresh lock should be kept, got %q (%v)", info.content, err)
+	}
+	lock.Release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Release should remove the lock file")
+	}
+}
+
+func TestReleaseKeepsLockOfAnotherHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tag.lock")
+
+	lock, err := AcquireLock(path, time.Second, time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireLock unexpected error: %v", err)
+	}
+
+	// The lock was taken over and another run holds it now
+	if err := os.WriteFile(path, []byte("1 other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock.Release()
+	if _, err := os.Stat(path); err != nil {
+		t.Error("Release should keep a lock it no longer holds")
+	}
+}
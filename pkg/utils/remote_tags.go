@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RemoteTag is a tag listed by git ls-remote
+type RemoteTag struct {
+	Name string
+	// Object is the tag object of an annotated tag, or the commit of a lightweight tag
+	Object string
+	// Commit is the commit the tag points to
+	Commit string
+}
+
+// TagSetDiff is the difference between the local tags and the tags on a remote
+type TagSetDiff struct {
+	// LocalOnly tags were never pushed or were deleted upstream
+	LocalOnly []string
+	// RemoteOnly tags were pushed by someone else and not fetched yet
+	RemoteOnly []string
+	// Changed tags exist on both sides but point to different objects
+	Changed []string
+}
+
+// IsEmpty reports whether the local and remote tags match
+func (d TagSetDiff) IsEmpty() bool {
+	return len(d.LocalOnly) == 0 && len(d.RemoteOnly) == 0 && len(d.Changed) == 0
+}
+
+// ListRemoteTags lists the tags on a remote with git ls-remote, without
+// fetching them or needing an up-to-date clone
+func ListRemoteTags(remote, dir string) ([]RemoteTag, error) {
+	output, err := cmdIn(fmt.Sprintf("git ls-remote --tags %s", remote), dir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags on %s: %v", remote, err)
+	}
+	return ParseRemoteTags(output), nil
+}
+
+// ParseRemoteTags parses git ls-remote --tags output. An annotated tag is
+// listed twice: with its tag object, and peeled (name^{}) with its commit.
+func ParseRemoteTags(output string) []RemoteTag {
+	byName := map[string]*RemoteTag{}
+	var names []string
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], "refs/tags/") {
+			continue
+		}
+
+		name := strings.TrimPrefix(fields[1], "refs/tags/")
+		peeled := strings.HasSuffix(name, "^{}")
+		name = strings.TrimSuffix(name, "^{}")
+
+		tag, exists := byName[name]
+		if !exists {
+			tag = &RemoteTag{Name: name}
+			byName[name] = tag
+			names = append(names, name)
+		}
+		if peeled {
+			tag.Commit = fields[0]
+			continue
+		}
+		tag.Object = fields[0]
+		if tag.Commit == "" {
+			tag.Commit = fields[0]
+		}
+	}
+
+	tags := make([]RemoteTag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, *byName[name])
+	}
+	return tags
+}
+
+// RemoteTagNames returns the names of remote tags
+func RemoteTagNames(tags []RemoteTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// ListLocalTagObjects maps each local tag to its object id, the same id
+// git ls-remote reports for the tag on a remote
+func ListLocalTagObjects(dir string) (map[string]string, error) {
+	output, err := cmdIn("git for-each-ref --format='%(refname:short) %(objectname)' refs/tags", dir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags: %v", err)
+	}
+
+	objects := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			objects[fields[0]] = fields[1]
+		}
+	}
+	return objects, nil
+}
+
+// CompareTagSets compares the local tags with the tags on a remote. Only tags
+// accepted by keep are compared, so unrelated tags do not show up.
+func CompareTagSets(local map[string]string, remote []RemoteTag, keep func(string) bool) TagSetDiff {
+	var diff TagSetDiff
+	remoteObjects := map[string]string{}
+
+	for _, tag := range remote {
+		if !keep(tag.Name) {
+			continue
+		}
+		remoteObjects[tag.Name] = tag.Object
+		object, exists := local[tag.Name]
+		switch {
+		case !exists:
+			diff.RemoteOnly = append(diff.RemoteOnly, tag.Name)
+		case object != tag.Object:
+			diff.Changed = append(diff.Changed, tag.Name)
+		}
+	}
+
+	for name := range local {
+		if _, exists := remoteObjects[name]; !exists && keep(name) {
+			diff.LocalOnly = append(diff.LocalOnly, name)
+		}
+	}
+
+	for _, tags := range [][]string{diff.LocalOnly, diff.RemoteOnly, diff.Changed} {
+		sort.Strings(tags)
+	}
+	return diff
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRemoteTags(t *testing.T) {
+	output := strings.Join([]string{
+		"aaa1\trefs/tags/stg6_1.2.0-1",
+		"ccc1\trefs/tags/stg6_1.2.0-1^{}",
+		"bbb2\trefs/tags/dev_1.3.0-1",
+		"ddd3\trefs/heads/main",
+		"",
+	}, "\n")
+
+	want := []RemoteTag{
+		{Name: "stg6_1.2.0-1", Object: "aaa1", Commit: "ccc1"},
+		{Name: "dev_1.3.0-1", Object: "bbb2", Commit: "bbb2"},
+	}
+	if got := ParseRemoteTags(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRemoteTags() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFilterEnvironmentTags(t *testing.T) {
+	tags := []string{"stg6_1.2.0-1", "api_stg6_1.3.0-1", "stg6_1.10.0-1", "dev_2.0.0-1", "other"}
+
+	if got, want := FilterEnvironmentTags(tags, "stg6", ""), []string{"stg6_1.10.0-1", "api_stg6_1.3.0-1", "stg6_1.2.0-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterEnvironmentTags() = %v, want %v", got, want)
+	}
+	if got, want := FilterEnvironmentTags(tags, "stg6", "api"), []string{"api_stg6_1.3.0-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterEnvironmentTags() with service = %v, want %v", got, want)
+	}
+	if got, want := FilterEnvironmentTags(tags, "dev", "web"), []string{"dev_2.0.0-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterEnvironmentTags() should fall back to plain tags, got %v, want %v", got, want)
+	}
+}
+
+func TestCompareTagSets(t *testing.T) {
+	dir := initTestRepo(t)
+	remote := filepath.Join(t.TempDir(), "origin.git")
+	runGit(t, dir, "init", "-q", "--bare", remote)
+	runGit(t, dir, "remote", "add", "origin", remote)
+
+	runGit(t, dir, "tag", "-a", "stg6_1.0.0-1", "-m", "pushed")
+	runGit(t, dir, "tag", "-a", "stg6_1.0.0-2", "-m", "moved")
+	runGit(t, dir, "tag", "notes", "-m", "ignored")
+	runGit(t, dir, "push", "-q", "origin", "--tags")
+
+	// Only on the remote, changed on the remote, and never pushed
+	runGit(t, dir, "tag", "-d", "stg6_1.0.0-1")
+	runGit(t, dir, "tag", "-f", "-a", "stg6_1.0.0-2", "-m", "moved locally")
+	runGit(t, dir, "tag", "-a", "stg6_1.0.0-3", "-m", "unpushed")
+	runGit(t, dir, "tag", "draft", "-m", "ignored")
+
+	remoteTags, err := ListRemoteTags("origin", dir)
+	if err != nil {
+		t.Fatalf("ListRemoteTags unexpected error: %v", err)
+	}
+	local, err := ListLocalTagObjects(dir)
+	if err != nil {
+		t.Fatalf("ListLocalTagObjects unexpected error: %v", err)
+	}
+
+	diff := CompareTagSets(local, remoteTags, IsTagValid)
+	want := TagSetDiff{
+		LocalOnly:  []string{"stg6_1.0.0-3"},
+		RemoteOnly: []string{"stg6_1.0.0-1"},
+		Changed:    []string{"stg6_1.0.0-2"},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("CompareTagSets() = %+v, want %+v", diff, want)
+	}
+	if diff.IsEmpty() {
+		t.Error("IsEmpty() should be false when tags differ")
+	}
+}
@@ -1,8 +1,8 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 )
@@ -15,8 +15,15 @@ const (
 	BumpMinor BumpType = "minor"
 	BumpPatch BumpType = "patch"
 	BumpAuto  BumpType = "auto"
+	// BumpGraduate releases the first stable version 1.0.0 from a 0.x version
+	BumpGraduate BumpType = "graduate"
+	// BumpInitial marks the first tag of an environment, which has no version to bump
+	BumpInitial BumpType = "initial"
 )
 
+// ErrNoTags is returned when an environment has no tags yet
+var ErrNoTags = errors.New("no tags found")
+
 // SemanticVersion represents a parsed semantic version
 type SemanticVersion struct {
 	Major      int
@@ -93,6 +100,13 @@ func BumpSemanticVersion(version string, bumpType BumpType) (string, error) {
 		sv.Patch = 0
 	case BumpPatch:
 		sv.Patch++
+	case BumpGraduate:
+		if sv.Major > 0 {
+			return "", fmt.Errorf("version %s is already stable, nothing to graduate", version)
+		}
+		sv.Major = 1
+		sv.Minor = 0
+		sv.Patch = 0
 	default:
 		return "", fmt.Errorf("unsupported bump type: %s", bumpType)
 	}
@@ -103,6 +117,35 @@ func BumpSemanticVersion(version string, bumpType BumpType) (string, error) {
 	return sv.String(), nil
 }
 
+// IsInitialDevelopment reports whether a version is in the 0.x initial development phase
+func IsInitialDevelopment(version string) bool {
+	sv, err := ParseSemanticVersion(version)
+	return err == nil && sv.Major == 0
+}
+
+// ApplyInitialDevelopment lowers a major bump to minor while a version is
+// still 0.x, as SemVer allows breaking changes in minor releases before 1.0.0.
+// It returns the bump to use and whether it was lowered.
+func ApplyInitialDevelopment(version string, bumpType BumpType) (BumpType, bool) {
+	if bumpType == BumpMajor && IsInitialDevelopment(version) {
+		return BumpMinor, true
+	}
+	return bumpType, false
+}
+
+// ValidateInitialVersion checks that a version can start an environment: a
+// plain MAJOR.MINOR.PATCH version without prefix or prerelease
+func ValidateInitialVersion(version string) error {
+	sv, err := ParseSemanticVersion(version)
+	if err != nil {
+		return err
+	}
+	if sv.String() != version || sv.Prerelease != "" {
+		return fmt.Errorf("initial version must be MAJOR.MINOR.PATCH, got: %s", version)
+	}
+	return nil
+}
+
 // CompareSemanticVersions compares two semantic versions
 // Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 func CompareSemanticVersions(v1, v2 string) (int, error) {
@@ -180,12 +223,18 @@ func BumpTagVersion(tag string, bumpType BumpType, env string, service string) (
 
 // GetCommitsBetweenTags gets commit messages between two tags
 func GetCommitsBetweenTags(tag1, tag2 string) ([]string, error) {
+	return GetCommitsBetweenTagsInDir(tag1, tag2, "", nil)
+}
+
+// GetCommitsBetweenTagsInDir gets commit messages between two tags in a specific directory,
+// limited to commits touching the given path globs when any are set
+func GetCommitsBetweenTagsInDir(tag1, tag2, dir string, paths []string) ([]string, error) {
 	if tag1 == "" || tag2 == "" {
 		return nil, fmt.Errorf("both tags must be provided")
 	}
 
 	// Use git log to get commits between tags
-	output, err := Cmd(fmt.Sprintf("git log --oneline --pretty=format:\"%%s\" %s..%s", tag1, tag2))
+	output, err := cmdIn(fmt.Sprintf("git log --oneline --pretty=format:\"%%s\" %s..%s%s", tag1, tag2, PathspecArgs(paths)), dir)
 	if err != nil {
 		return nil, fmt.Errorf("error getting commits between tags: %v", err)
 	}
@@ -198,38 +247,30 @@ func GetCommitsBetweenTags(tag1, tag2 string) ([]string, error) {
 	return commits, nil
 }
 
-// DetectBumpType analyzes commit messages to suggest version bump type
-func DetectBumpType(commits []string) BumpType {
-	hasBreaking := false
-	hasFeature := false
-	hasFix := false
-
-	// Conventional commit patterns
-	breakingPattern := regexp.MustCompile(`(?i)(BREAKING|BREAKING CHANGE|!:)`)
-	featurePattern := regexp.MustCompile(`(?i)^(feat|feature)(\(.+\))?:`)
-	fixPattern := regexp.MustCompile(`(?i)^(fix|bugfix)(\(.+\))?:`)
-
-	for _, commit := range commits {
-		if breakingPattern.MatchString(commit) {
-			hasBreaking = true
-		} else if featurePattern.MatchString(commit) {
-			hasFeature = true
-		} else if fixPattern.MatchString(commit) {
-			hasFix = true
-		}
+// GetCommitMessagesInDir gets the hash, subject and body of the commits in a
+// revision range in a specific directory, limited to the given path globs
+func GetCommitMessagesInDir(revRange, dir string, paths []string) ([]CommitMessage, error) {
+	output, err := cmdIn(fmt.Sprintf("git log --format=\"%%H%%x1f%%B%%x1e\" %s%s", revRange, PathspecArgs(paths)), dir)
+	if err != nil {
+		return nil, fmt.Errorf("error getting commits for %s: %v", revRange, err)
 	}
 
-	// Determine bump type based on commit analysis
-	if hasBreaking {
-		return BumpMajor
-	} else if hasFeature {
-		return BumpMinor
-	} else if hasFix {
-		return BumpPatch
+	commits := []CommitMessage{}
+	for _, record := range strings.Split(output, "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		commits = append(commits, ParseCommitMessage(parts[0], parts[1]))
 	}
 
-	// Default to patch for any other changes
-	return BumpPatch
+	return commits, nil
+}
+
+// DetectBumpType analyzes commit messages to suggest version bump type
+// using the default commit taxonomy
+func DetectBumpType(commits []string) BumpType {
+	return DefaultCommitTaxonomy().DetectBumpType(commits)
 }
 
 // ValidateSemanticVersionBump validates if a version bump is appropriate
@@ -254,25 +295,27 @@ func ValidateSemanticVersionBump(currentTag, proposedVersion string, bumpType Bu
 
 // GetLatestSemanticVersion finds the latest semantic version for an environment
 func GetLatestSemanticVersion(env string, service string) (string, string, error) {
+	return GetLatestSemanticVersionInDir(env, service, "")
+}
+
+// GetLatestSemanticVersionInDir finds the latest semantic version for an environment in a specific directory
+func GetLatestSemanticVersionInDir(env, service, dir string) (string, string, error) {
 	// Get all tags for the environment
 	pattern := env
 	if service != "" {
 		pattern = service + "_" + env
 	}
 
-	output, err := Cmd(fmt.Sprintf("git tag -l '%s_*' --sort=-version:refname", pattern))
+	output, err := cmdIn(fmt.Sprintf("git tag -l '%s_*' --sort=-version:refname", pattern), dir)
 	if err != nil {
 		return "", "", fmt.Errorf("error listing tags: %v", err)
 	}
 
 	if output == "" {
-		return "", "", fmt.Errorf("no tags found for environment: %s", env)
+		return "", "", fmt.Errorf("%w for environment: %s", ErrNoTags, env)
 	}
 
 	tags := strings.Split(output, "\n")
-	if len(tags) == 0 {
-		return "", "", fmt.Errorf("no tags found for environment: %s", env)
-	}
 
 	// Return the first (latest) tag and its version
 	latestTag := tags[0]
@@ -283,3 +326,54 @@ func GetLatestSemanticVersion(env string, service string) (string, string, error
 
 	return latestTag, version, nil
 }
+
+// EnvironmentVersion is the latest version tagged in an environment
+type EnvironmentVersion struct {
+	Environment string
+	Tag         string
+	Version     string
+}
+
+// LatestEnvironmentVersions returns the latest version of every environment
+// that has tags, in promotion order
+func LatestEnvironmentVersions(service, dir string) ([]EnvironmentVersion, error) {
+	var versions []EnvironmentVersion
+	for _, env := range ENVS {
+		tag, version, err := GetLatestSemanticVersionInDir(env, service, dir)
+		if errors.Is(err, ErrNoTags) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, EnvironmentVersion{Environment: env, Tag: tag, Version: version})
+	}
+	return versions, nil
+}
+
+// HighestEnvironmentVersion returns the environment version with the highest
+// semantic version, preferring the earliest environment on ties
+func HighestEnvironmentVersion(versions []EnvironmentVersion) (EnvironmentVersion, bool) {
+	var highest EnvironmentVersion
+	found := false
+	for _, candidate := range versions {
+		if !found {
+			highest, found = candidate, true
+			continue
+		}
+		if cmp, err := CompareSemanticVersions(candidate.Version, highest.Version); err == nil && cmp > 0 {
+			highest = candidate
+		}
+	}
+	return highest, found
+}
+
+// DownstreamEnvironments returns the environments after env in the promotion order
+func DownstreamEnvironments(env string) []string {
+	for i, candidate := range ENVS {
+		if candidate == env {
+			return ENVS[i+1:]
+		}
+	}
+	return nil
+}
@@ -93,6 +93,8 @@ func TestBumpSemanticVersion(t *testing.T) {
 		{"1.2.3-alpha", BumpPatch, "1.2.4", false}, // removes prerelease
 		{"invalid", BumpPatch, "", true},
 		{"1.2.3", "invalid", "", true},
+		{"0.9.2", BumpGraduate, "1.0.0", false},
+		{"1.0.0", BumpGraduate, "", true}, // already stable
 	}
 
 	for _, tt := range tests {
@@ -247,7 +249,8 @@ func TestDetectBumpType(t *testing.T) {
 		{[]string{"feat: add new feature"}, BumpMinor},
 		{[]string{"fix: resolve bug"}, BumpPatch},
 		{[]string{"feat!: breaking change"}, BumpMajor},
-		{[]string{"BREAKING CHANGE: remove API"}, BumpMajor},
+		{[]string{"refactor: remove API\n\nBREAKING CHANGE: remove API"}, BumpMajor},
+		{[]string{"BREAKING CHANGE: remove API"}, BumpPatch}, // a footer only counts in the body
 		{[]string{"feat: new feature", "fix: bug fix"}, BumpMinor},
 		{[]string{"feat!: breaking", "feat: feature"}, BumpMajor},
 		{[]string{"docs: update readme"}, BumpPatch},
@@ -296,3 +299,47 @@ func TestValidateSemanticVersionBump(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyInitialDevelopment(t *testing.T) {
+	tests := []struct {
+		version     string
+		bumpType    BumpType
+		want        BumpType
+		wantLowered bool
+	}{
+		{"0.4.1", BumpMajor, BumpMinor, true},
+		{"0.4.1", BumpMinor, BumpMinor, false},
+		{"0.4.1", BumpPatch, BumpPatch, false},
+		{"1.4.1", BumpMajor, BumpMajor, false},
+		{"invalid", BumpMajor, BumpMajor, false},
+	}
+
+	for _, tt := range tests {
+		got, lowered := ApplyInitialDevelopment(tt.version, tt.bumpType)
+		if got != tt.want || lowered != tt.wantLowered {
+			t.Errorf("ApplyInitialDevelopment(%q, %q) = %q, %t, want %q, %t",
+				tt.version, tt.bumpType, got, lowered, tt.want, tt.wantLowered)
+		}
+	}
+}
+
+func TestValidateInitialVersion(t *testing.T) {
+	tests := []struct {
+		version   string
+		shouldErr bool
+	}{
+		{"0.1.0", false},
+		{"1.0.0", false},
+		{"0.1", true},
+		{"v0.1.0", true},
+		{"0.1.0-rc1", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateInitialVersion(tt.version)
+		if (err != nil) != tt.shouldErr {
+			t.Errorf("ValidateInitialVersion(%q) error = %v, shouldErr %t", tt.version, err, tt.shouldErr)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tagMetadataCacheDir holds one cache file per repository with the metadata
+// of its tags keyed by tag object id. Tag objects and commits never change,
+// so entries stay valid across runs; ids no longer tagged are pruned.
+const tagMetadataCacheDir = "tag-metadata"
+
+// tagMetadataCacheVersion changes whenever TagMetadata does, so caches written
+// by older versions are read again from git
+const tagMetadataCacheVersion = 2
+
+// tagMetadataFormat reads a tag and the commit it points to in one record.
+// Annotated tags have the commit fields peeled (*), lightweight tags plain.
+const tagMetadataFormat = "%(refname:short)%00%(objectname)%00%(*objectname)%00%(committerdate:iso-strict)%00%(*committerdate:iso-strict)%00%(taggerdate:iso-strict)%00%(contents)%1e"
+
+// TagMetadata is the commit a tag points to, when it was tagged and committed,
+// and the tag message
+type TagMetadata struct {
+	Commit string `json:"commit"`
+	// TagDate is the tagger date of an annotated tag, or the commit date of a
+	// lightweight tag, which has no date of its own
+	TagDate    time.Time `json:"tag_date"`
+	CommitDate time.Time `json:"commit_date"`
+	Message    string    `json:"message"`
+}
+
+// tagMetadataCache is the on-disk cache of tag metadata by object id
+type tagMetadataCache struct {
+	Version int                    `json:"version"`
+	Tags    map[string]TagMetadata `json:"tags"`
+}
+
+// CacheDir returns the esh-cli cache directory, ~/.cache/esh-cli unless
+// XDG_CACHE_HOME points elsewhere
+func CacheDir() (string, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(base, "esh-cli"), nil
+}
+
+// LoadTagMetadata returns the metadata of every local tag, keyed by tag name.
+// With a cache for the repository, only the tag object ids are listed and
+// the cached metadata is used when every id is cached; otherwise all tags are
+// read with a single git for-each-ref call and the cache is rewritten with
+// just the current ids. The cache is best effort: when it cannot be read or
+// written, the tags are simply read from git.
+func LoadTagMetadata(dir string) (map[string]TagMetadata, error) {
+	cachePath := tagMetadataCachePath(dir)
+	cache := loadTagMetadataCache(cachePath)
+
+	if len(cache) > 0 {
+		objects, err := ListLocalTagObjects(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata, current := cachedTagMetadata(cache, objects)
+		if metadata != nil {
+			if len(current) != len(cache) {
+				saveTagMetadataCache(cachePath, current)
+			}
+			return metadata, nil
+		}
+	}
+
+	metadata, objects, err := readTagMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]TagMetadata, len(objects))
+	for tag, object := range objects {
+		current[object] = metadata[tag]
+	}
+	saveTagMetadataCache(cachePath, current)
+
+	return metadata, nil
+}
+
+// cachedTagMetadata returns the metadata of the tags from the cache and the
+// cache entries still tagged, or nil when any tag is not cached
+func cachedTagMetadata(cache map[string]TagMetadata, objects map[string]string) (map[string]TagMetadata, map[string]TagMetadata) {
+	metadata := make(map[string]TagMetadata, len(objects))
+	current := make(map[string]TagMetadata, len(objects))
+	for tag, object := range objects {
+		cached, ok := cache[object]
+		if !ok {
+			return nil, nil
+		}
+		metadata[tag] = cached
+		current[object] = cached
+	}
+	return metadata, current
+}
+
+// ReadTagMetadata reads the metadata of every local tag with a single git
+// for-each-ref call, without the cache
+func ReadTagMetadata(dir string) (map[string]TagMetadata, error) {
+	metadata, _, err := readTagMetadata(dir)
+	return metadata, err
+}
+
+// readTagMetadata reads the metadata and the object id of every local tag
+// with a single git for-each-ref call
+func readTagMetadata(dir string) (map[string]TagMetadata, map[string]string, error) {
+	output, err := cmdIn(fmt.Sprintf("git for-each-ref --format='%s' refs/tags", tagMetadataFormat), dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading tags: %v", err)
+	}
+	metadata, objects := parseTagMetadata(output)
+	return metadata, objects, nil
+}
+
+// parseTagMetadata parses the records written with tagMetadataFormat into the
+// metadata and the object id of each tag
+func parseTagMetadata(output string) (map[string]TagMetadata, map[string]string) {
+	metadata := map[string]TagMetadata{}
+	objects := map[string]string{}
+	for _, record := range strings.Split(output, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 7)
+		if len(fields) != 7 || fields[0] == "" {
+			continue
+		}
+
+		commit, commitDate := fields[1], fields[3]
+		if fields[2] != "" {
+			commit, commitDate = fields[2], fields[4]
+		}
+
+		entry := TagMetadata{Commit: commit, Message: strings.TrimSpace(fields[6])}
+		entry.CommitDate, _ = time.Parse(time.RFC3339, commitDate)
+		entry.TagDate = entry.CommitDate
+		if tagDate, err := time.Parse(time.RFC3339, fields[5]); err == nil {
+			entry.TagDate = tagDate
+		}
+		metadata[fields[0]] = entry
+		objects[fields[0]] = fields[1]
+	}
+	return metadata, objects
+}
+
+// tagMetadataCachePath returns the cache file of the repository containing
+// dir, named after a hash of its common git directory so that worktrees
+// share it, or an empty string when there is none
+func tagMetadataCachePath(dir string) string {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return ""
+	}
+
+	gitDir, err := cmdIn("git rev-parse --git-common-dir", dir)
+	if err != nil || gitDir == "" {
+		return ""
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	if gitDir, err = filepath.Abs(gitDir); err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(gitDir))
+	return filepath.Join(cacheDir, tagMetadataCacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadTagMetadataCache reads a cache file, returning an empty cache when it
+// is missing, unreadable or written by another version
+func loadTagMetadataCache(path string) map[string]TagMetadata {
+	if path == "" {
+		return map[string]TagMetadata{}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return map[string]TagMetadata{}
+	}
+
+	var cache tagMetadataCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Version != tagMetadataCacheVersion || cache.Tags == nil {
+		return map[string]TagMetadata{}
+	}
+	return cache.Tags
+}
+
+// saveTagMetadataCache writes a cache file through a temporary file, so a
+// concurrent reader never sees a partial cache
+func saveTagMetadataCache(path string, cache map[string]TagMetadata) {
+	if path == "" {
+		return
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(tagMetadataCache{Version: tagMetadataCacheVersion, Tags: cache})
+	if err != nil {
+		return
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+	}
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTagMetadata(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("GIT_COMMITTER_DATE", "2024-01-01T10:00:00Z")
+	dir := initTestRepo(t)
+	head := strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD"))
+
+	// The annotated tag promotes the commit months later
+	runGit(t, dir, "tag", "dev_1.0.0-1")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-06-01T10:00:00Z")
+	runGit(t, dir, "tag", "-a", "stg6_1.0.0-1", "-m", "first release")
+
+	metadata, err := LoadTagMetadata(dir)
+	if err != nil {
+		t.Fatalf("LoadTagMetadata unexpected error: %v", err)
+	}
+
+	annotated, lightweight := metadata["stg6_1.0.0-1"], metadata["dev_1.0.0-1"]
+	if annotated.Commit != head || lightweight.Commit != head {
+		t.Errorf("tags should point to %s, got %s and %s", head, annotated.Commit, lightweight.Commit)
+	}
+	if annotated.Message != "first release" || lightweight.Message != "initial commit" {
+		t.Errorf("unexpected messages %q and %q", annotated.Message, lightweight.Message)
+	}
+	committed := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tagged := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	if !annotated.CommitDate.Equal(committed) || !lightweight.CommitDate.Equal(committed) {
+		t.Errorf("both tags should have the commit date %v, got %v and %v", committed, annotated.CommitDate, lightweight.CommitDate)
+	}
+	if !annotated.TagDate.Equal(tagged) {
+		t.Errorf("annotated tag date = %v, want the tagger date %v", annotated.TagDate, tagged)
+	}
+	if !lightweight.TagDate.Equal(committed) {
+		t.Errorf("lightweight tag date = %v, want the commit date %v", lightweight.TagDate, committed)
+	}
+
+	// Cached tags are not read from git again
+	cachePath := tagMetadataCachePath(dir)
+	cacheDir, _ := CacheDir()
+	if filepath.Dir(cachePath) != filepath.Join(cacheDir, tagMetadataCacheDir) {
+		t.Fatalf("cache path %q should be under %s", cachePath, cacheDir)
+	}
+	writeTestFile(t, dir, "sub/file.txt", "nested\n")
+	if nested := tagMetadataCachePath(filepath.Join(dir, "sub")); nested != cachePath {
+		t.Errorf("a directory in the repository should share its cache, got %q and %q", nested, cachePath)
+	}
+	cache := loadTagMetadataCache(cachePath)
+	object := strings.TrimSpace(runGit(t, dir, "rev-parse", "stg6_1.0.0-1"))
+	entry, ok := cache[object]
+	if !ok {
+		t.Fatalf("%s should be cached by its object id %s", "stg6_1.0.0-1", object)
+	}
+	entry.Message = "from cache"
+	cache[object] = entry
+	data, _ := json.Marshal(tagMetadataCache{Version: tagMetadataCacheVersion, Tags: cache})
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata, _ := LoadTagMetadata(dir); metadata["stg6_1.0.0-1"].Message != "from cache" {
+		t.Errorf("cached metadata should be used, got %q", metadata["stg6_1.0.0-1"].Message)
+	}
+
+	// A new tag is read and cached
+	runGit(t, dir, "tag", "-a", "stg6_1.0.0-2", "-m", "second release")
+	if metadata, _ := LoadTagMetadata(dir); metadata["stg6_1.0.0-2"].Message != "second release" {
+		t.Errorf("new tag should be read, got %+v", metadata["stg6_1.0.0-2"])
+	}
+
+	// Deleted tags are pruned from the cache
+	runGit(t, dir, "tag", "-d", "stg6_1.0.0-2")
+	if metadata, _ := LoadTagMetadata(dir); len(metadata) != 2 {
+		t.Errorf("deleted tag should not be returned, got %v", metadata)
+	}
+	if pruned := loadTagMetadataCache(cachePath); len(pruned) != 2 {
+		t.Errorf("cache should keep only the 2 current tag objects, got %d", len(pruned))
+	}
+
+	// Other repositories have their own cache
+	other := initTestRepo(t)
+	runGit(t, other, "tag", "-a", "stg6_1.0.0-1", "-m", "other release")
+	if otherPath := tagMetadataCachePath(other); otherPath == cachePath {
+		t.Errorf("repositories should not share a cache file %s", cachePath)
+	}
+	if metadata, _ := LoadTagMetadata(other); metadata["stg6_1.0.0-1"].Message != "other release" {
+		t.Errorf("other repository tag = %+v", metadata["stg6_1.0.0-1"])
+	}
+	if len(loadTagMetadataCache(cachePath)) != 2 {
+		t.Error("loading another repository should not change this repository's cache")
+	}
+
+	// A cache written by another version is ignored
+	data, _ = json.Marshal(tagMetadataCache{Version: tagMetadataCacheVersion - 1, Tags: cache})
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if metadata, _ := LoadTagMetadata(dir); metadata["stg6_1.0.0-1"].Message != "first release" {
+		t.Errorf("an outdated cache should be read again from git, got %q", metadata["stg6_1.0.0-1"].Message)
+	}
+
+	// A broken cache is ignored
+	if err := os.WriteFile(cachePath, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if metadata, err := LoadTagMetadata(dir); err != nil || metadata["stg6_1.0.0-1"].Message != "first release" {
+		t.Errorf("a broken cache should be read again from git, got %+v (%v)", metadata["stg6_1.0.0-1"], err)
+	}
+}
+
+// createTags creates n annotated tags across the environments with a single
+// git fast-import call
+func createTags(tb testing.TB, dir string, n int) {
+	tb.Helper()
+
+	var stream strings.Builder
+	for i := 0; i < n; i++ {
+		env := ENVS[i%len(ENVS)]
+		message := fmt.Sprintf("release %d", i)
+		fmt.Fprintf(&stream, "tag %s_%d.%d.0-1\nfrom HEAD\ntagger Test <test@example.com> %d +0000\ndata %d\n%s\n",
+			env, i/100, i%100, 1700000000+i, len(message), message)
+	}
+
+	cmd := exec.Command("git", "fast-import", "--quiet")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stream.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		tb.Fatalf("git fast-import failed: %v\n%s", err, output)
+	}
+}
+
+func BenchmarkLoadTagMetadata(b *testing.B) {
+	b.Setenv("XDG_CACHE_HOME", b.TempDir())
+	dir := initTestRepo(b)
+	createTags(b, dir, 3000)
+
+	b.Run("for-each-ref", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if metadata, err := ReadTagMetadata(dir); err != nil || len(metadata) != 3000 {
+				b.Fatalf("ReadTagMetadata() read %d tags: %v", len(metadata), err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		if _, err := LoadTagMetadata(dir); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if metadata, err := LoadTagMetadata(dir); err != nil || len(metadata) != 3000 {
+				b.Fatalf("LoadTagMetadata() read %d tags: %v", len(metadata), err)
+			}
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TagInfo holds the components of a parsed env tag
+type TagInfo struct {
+	Tag         string
+	Service     string
+	Environment string
+	Version     string
+	Release     int
+	HotFix      int
+}
+
+// ParseTag splits a valid tag into service, environment, version and release numbers
+func ParseTag(tag string) (*TagInfo, error) {
+	if !IsTagValid(tag) {
+		return nil, fmt.Errorf("invalid tag format: %s", tag)
+	}
+
+	parts := strings.Split(tag, "_")
+	info := &TagInfo{Tag: tag, Release: -1}
+
+	if len(parts) == 3 {
+		info.Service = parts[0]
+		info.Environment = parts[1]
+	} else {
+		info.Environment = parts[0]
+	}
+
+	versionReleaseParts := strings.Split(parts[len(parts)-1], "-")
+	info.Version = versionReleaseParts[0]
+
+	if len(versionReleaseParts) == 2 {
+		releaseParts := strings.Split(versionReleaseParts[1], ".")
+		info.Release, _ = strconv.Atoi(releaseParts[0])
+		if len(releaseParts) == 2 {
+			info.HotFix, _ = strconv.Atoi(releaseParts[1])
+		}
+	}
+
+	return info, nil
+}
+
+// IsHotFix reports whether the tag carries a hot fix number (X.Y.Z-N.M)
+func (ti *TagInfo) IsHotFix() bool {
+	return ti.HotFix > 0
+}
+
+// CompareTags compares two valid tags by semantic version, release and hot fix number
+// Returns: -1 if tag1 < tag2, 0 if equal, 1 if tag1 > tag2
+func CompareTags(tag1, tag2 string) (int, error) {
+	info1, err := ParseTag(tag1)
+	if err != nil {
+		return 0, err
+	}
+	info2, err := ParseTag(tag2)
+	if err != nil {
+		return 0, err
+	}
+
+	cmp, err := CompareSemanticVersions(info1.Version, info2.Version)
+	if err != nil || cmp != 0 {
+		return cmp, err
+	}
+
+	if info1.Release != info2.Release {
+		if info1.Release < info2.Release {
+			return -1, nil
+		}
+		return 1, nil
+	}
+
+	if info1.HotFix != info2.HotFix {
+		if info1.HotFix < info2.HotFix {
+			return -1, nil
+		}
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// SortTagsByVersion sorts valid tags from newest to oldest version
+func SortTagsByVersion(tags []string) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		cmp, err := CompareTags(tags[i], tags[j])
+		return err == nil && cmp > 0
+	})
+}
+
+// ListEnvironmentTags lists the valid tags of an environment, newest first.
+// Without a service, both env_version and service_env_version tags are listed.
+// With a service, service_env_version tags are listed, falling back to plain
+// env_version tags for repositories that hold a single service.
+func ListEnvironmentTags(env, service, dir string) ([]string, error) {
+	if service != "" {
+		tags, err := listTags(env, service, fmt.Sprintf("'%s_%s_*'", service, env), dir)
+		if err != nil || len(tags) > 0 {
+			return tags, err
+		}
+		return listTags(env, "", fmt.Sprintf("'%s_*'", env), dir)
+	}
+
+	return listTags(env, "", fmt.Sprintf("'%s_*' '*_%s_*'", env, env), dir)
+}
+
+// FilterEnvironmentTags keeps the valid tags of an environment, newest first,
+// with the same service rules as ListEnvironmentTags
+func FilterEnvironmentTags(tags []string, env, service string) []string {
+	if service != "" {
+		if filtered := filterTags(tags, env, service); len(filtered) > 0 {
+			return filtered
+		}
+	}
+	return filterTags(tags, env, "")
+}
+
+// listTags lists tags matching the quoted patterns that belong to env (and service, if set)
+func listTags(env, service, patterns, dir string) ([]string, error) {
+	output, err := cmdIn(fmt.Sprintf("git tag -l %s", patterns), dir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags: %v", err)
+	}
+
+	return filterTags(strings.Split(output, "\n"), env, service), nil
+}
+
+// filterTags keeps the valid tags that belong to env (and service, if set), newest first
+func filterTags(candidates []string, env, service string) []string {
+	var tags []string
+	for _, tag := range candidates {
+		tag = strings.TrimSpace(tag)
+		info, err := ParseTag(tag)
+		if err != nil || info.Environment != env {
+			continue
+		}
+		if service != "" && info.Service != service {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	SortTagsByVersion(tags)
+	return tags
+}
+
+// PathspecArgs builds the git pathspec arguments restricting a command to the given globs
+func PathspecArgs(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" --")
+	for _, path := range paths {
+		sb.WriteString(" '" + strings.ReplaceAll(":(glob)"+path, "'", `'\''`) + "'")
+	}
+	return sb.String()
+}
+
+// cmdIn executes a shell command in dir, or in the current directory when dir is empty
+func cmdIn(command, dir string) (string, error) {
+	if dir != "" {
+		return CmdInDir(command, dir)
+	}
+	return Cmd(command)
+}
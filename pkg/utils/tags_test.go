@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// initTestRepo creates a git repository with one commit in a temporary directory
+func initTestRepo(t testing.TB) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	writeTestFile(t, dir, "README.md", "test\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "initial commit")
+	return dir
+}
+
+// runGit runs a git command in dir with a fixed identity
+func runGit(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+// writeTestFile writes a file relative to dir, creating parent directories
+func writeTestFile(t testing.TB, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag       string
+		want      TagInfo
+		shouldErr bool
+	}{
+		{"stg6_1.2.3-4", TagInfo{Tag: "stg6_1.2.3-4", Environment: "stg6", Version: "1.2.3", Release: 4}, false},
+		{"api_stg6_1.2.3-4.2", TagInfo{Tag: "api_stg6_1.2.3-4.2", Service: "api", Environment: "stg6", Version: "1.2.3", Release: 4, HotFix: 2}, false},
+		{"dev_0.1.0", TagInfo{Tag: "dev_0.1.0", Environment: "dev", Version: "0.1.0", Release: -1}, false},
+		{"invalid", TagInfo{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTag(tt.tag)
+		if tt.shouldErr {
+			if err == nil {
+				t.Errorf("ParseTag(%q) expected error, got nil", tt.tag)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTag(%q) unexpected error: %v", tt.tag, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("ParseTag(%q) = %+v, want %+v", tt.tag, *got, tt.want)
+		}
+	}
+}
+
+func TestSortTagsByVersion(t *testing.T) {
+	tags := []string{"stg6_1.2.0-1", "stg6_1.10.0-1", "stg6_1.2.0-2", "stg6_1.2.0-1.1", "stg6_1.2.0"}
+	SortTagsByVersion(tags)
+
+	want := []string{"stg6_1.10.0-1", "stg6_1.2.0-2", "stg6_1.2.0-1.1", "stg6_1.2.0-1", "stg6_1.2.0"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("SortTagsByVersion() = %v, want %v", tags, want)
+	}
+}
+
+func TestPathspecArgs(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"services/api/**"}, " -- ':(glob)services/api/**'"},
+		{[]string{"a/**", "lib/it's/**"}, ` -- ':(glob)a/**' ':(glob)lib/it'\''s/**'`},
+	}
+
+	for _, tt := range tests {
+		got := PathspecArgs(tt.paths)
+		if got != tt.want {
+			t.Errorf("PathspecArgs(%v) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestListEnvironmentTags(t *testing.T) {
+	dir := initTestRepo(t)
+	for _, tag := range []string{"stg6_1.2.0-1", "stg6_1.10.0-1", "api_stg6_2.0.0-1", "dev_3.0.0-1", "stg6_notaversion"} {
+		runGit(t, dir, "tag", tag)
+	}
+
+	tests := []struct {
+		env     string
+		service string
+		want    []string
+	}{
+		{"stg6", "", []string{"api_stg6_2.0.0-1", "stg6_1.10.0-1", "stg6_1.2.0-1"}},
+		{"stg6", "api", []string{"api_stg6_2.0.0-1"}},
+		{"stg6", "worker", []string{"stg6_1.10.0-1", "stg6_1.2.0-1"}}, // falls back to plain env tags
+		{"demo", "", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := ListEnvironmentTags(tt.env, tt.service, dir)
+		if err != nil {
+			t.Errorf("ListEnvironmentTags(%q, %q) unexpected error: %v", tt.env, tt.service, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ListEnvironmentTags(%q, %q) = %v, want %v", tt.env, tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestGetCommitsBetweenTagsInDir(t *testing.T) {
+	dir := initTestRepo(t)
+	runGit(t, dir, "tag", "stg6_1.0.0-1")
+
+	writeTestFile(t, dir, "services/api/main.go", "package main\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "feat(api): add endpoint")
+
+	writeTestFile(t, dir, "services/worker/main.go", "package main\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "fix(worker): retry jobs")
+	runGit(t, dir, "tag", "stg6_1.1.0-1")
+
+	all, err := GetCommitsBetweenTagsInDir("stg6_1.0.0-1", "stg6_1.1.0-1", dir, nil)
+	if err != nil {
+		t.Fatalf("GetCommitsBetweenTagsInDir unexpected error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 commits without paths, got %v", all)
+	}
+
+	api, err := GetCommitsBetweenTagsInDir("stg6_1.0.0-1", "stg6_1.1.0-1", dir, []string{"services/api/**"})
+	if err != nil {
+		t.Fatalf("GetCommitsBetweenTagsInDir unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(api, []string{"feat(api): add endpoint"}) {
+		t.Errorf("Expected only the api commit, got %v", api)
+	}
+}
+
+func TestGetCommitMessagesInDir(t *testing.T) {
+	dir := initTestRepo(t)
+	runGit(t, dir, "tag", "stg6_1.0.0-1")
+
+	writeTestFile(t, dir, "api.go", "package api\n")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "refactor(api): split handlers", "-m", "BREAKING CHANGE: handlers moved")
+
+	commits, err := GetCommitMessagesInDir("stg6_1.0.0-1..HEAD", dir, nil)
+	if err != nil {
+		t.Fatalf("GetCommitMessagesInDir unexpected error: %v", err)
+	}
+	if len(commits) != 1 {
+		t.Fatalf("Expected 1 commit, got %+v", commits)
+	}
+	if len(commits[0].Hash) != 40 || commits[0].Subject != "refactor(api): split handlers" ||
+		commits[0].Body != "BREAKING CHANGE: handlers moved" {
+		t.Errorf("Unexpected commit message: %+v", commits[0])
+	}
+
+	empty, err := GetCommitMessagesInDir("HEAD..HEAD", dir, nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected no commits for empty range, got %+v, %v", empty, err)
+	}
+}
+
+func TestGetLatestSemanticVersionInDirNoTags(t *testing.T) {
+	dir := initTestRepo(t)
+
+	_, _, err := GetLatestSemanticVersionInDir("dev", "", dir)
+	if !errors.Is(err, ErrNoTags) {
+		t.Fatalf("Expected ErrNoTags, got %v", err)
+	}
+	if err.Error() != "no tags found for environment: dev" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+
+	runGit(t, dir, "tag", "dev_0.1.0-1")
+	tag, version, err := GetLatestSemanticVersionInDir("dev", "", dir)
+	if err != nil || tag != "dev_0.1.0-1" || version != "0.1.0" {
+		t.Errorf("GetLatestSemanticVersionInDir() = %q, %q, %v", tag, version, err)
+	}
+}
+
+func TestLatestEnvironmentVersions(t *testing.T) {
+	dir := initTestRepo(t)
+	for _, tag := range []string{"dev_2.3.0-1", "dev_2.2.0-4", "stg6_1.9.0-2", "production2_1.8.0-1", "api_dev_9.0.0-1"} {
+		runGit(t, dir, "tag", tag)
+	}
+
+	versions, err := LatestEnvironmentVersions("", dir)
+	if err != nil {
+		t.Fatalf("LatestEnvironmentVersions unexpected error: %v", err)
+	}
+
+	want := []EnvironmentVersion{
+		{Environment: "dev", Tag: "dev_2.3.0-1", Version: "2.3.0"},
+		{Environment: "stg6", Tag: "stg6_1.9.0-2", Version: "1.9.0"},
+		{Environment: "production2", Tag: "production2_1.8.0-1", Version: "1.8.0"},
+	}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("LatestEnvironmentVersions() = %+v, want %+v", versions, want)
+	}
+
+	highest, found := HighestEnvironmentVersion(versions)
+	if !found || highest.Tag != "dev_2.3.0-1" {
+		t.Errorf("HighestEnvironmentVersion() = %+v, %t, want dev_2.3.0-1", highest, found)
+	}
+
+	if _, found := HighestEnvironmentVersion(nil); found {
+		t.Error("HighestEnvironmentVersion(nil) should find nothing")
+	}
+}
+
+func TestDownstreamEnvironments(t *testing.T) {
+	if got := DownstreamEnvironments("stg6"); !reflect.DeepEqual(got, []string{"demo", "production2"}) {
+		t.Errorf("DownstreamEnvironments(stg6) = %v", got)
+	}
+	if got := DownstreamEnvironments("production2"); len(got) != 0 {
+		t.Errorf("DownstreamEnvironments(production2) = %v, want none", got)
+	}
+	if got := DownstreamEnvironments("unknown"); got != nil {
+		t.Errorf("DownstreamEnvironments(unknown) = %v, want nil", got)
+	}
+}
@@ -304,9 +304,10 @@ func FindLastTagAndCommentInDir(env, version, service, dir string) (string, stri
 	return bestTag, bestComment, nil
 }
 
-// IsReleaseBranch checks if branch is a release branch
+// IsReleaseBranch checks if branch is a release branch under the default
+// branch rules, which match both release_X.Y and release/*
 func IsReleaseBranch(branch string) bool {
-	return ReleaseBranchPattern.MatchString(branch)
+	return DefaultBranchRuleSet().Match(branch).Rule.Type == BranchTypeRelease
 }
 
 // ContainsString checks if a slice contains a string
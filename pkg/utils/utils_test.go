@@ -111,6 +111,7 @@ func TestIsReleaseBranch(t *testing.T) {
 		{"main", false},
 		{"feature/test", false},
 		{"release_1", false},
+		{"release/1.2.0", true},
 	}
 
 	for _, tt := range tests {